@@ -1,21 +1,41 @@
 package main
 
 import (
+    "context"
     "encoding/json"
     "log"
+    "errors"
+    "fmt"
+    "math/big"
     "net/http"
     "os"
+    "strings"
+    "time"
     
+    "github.com/ethereum/go-ethereum/common"
     "github.com/gorilla/mux"
     "github.com/joho/godotenv"
+    "github.com/yourorg/proptoken-oracle/internal/config"
+    "github.com/yourorg/proptoken-oracle/internal/consensus"
+    "github.com/yourorg/proptoken-oracle/internal/geo"
     "github.com/yourorg/proptoken-oracle/internal/handlers"
+    "github.com/yourorg/proptoken-oracle/internal/indexer"
+    "github.com/yourorg/proptoken-oracle/internal/jobs"
     "github.com/yourorg/proptoken-oracle/internal/integrations"
     "github.com/yourorg/proptoken-oracle/internal/crypto"
     "github.com/yourorg/proptoken-oracle/internal/blockchain"
+    "github.com/yourorg/proptoken-oracle/internal/store"
     "github.com/yourorg/proptoken-oracle/pkg/types"
 )
 
 var aggregator *handlers.OracleAggregator
+var pipeline jobs.Pipeline
+var resultStore *store.Store
+var jobQueue *jobs.Queue
+var attestationVerifier *handlers.AttestationVerifier
+var runMode string
+var oracleAddress string
+var consensusCfg config.ConsensusConfig
 
 func main() {
     // 1. Load Env
@@ -23,73 +43,396 @@ func main() {
         log.Println("Warning: .env file not found, using environment variables")
     }
     
-    // 2. Init Clients (Mocked/Free Tier)
-    satClient := integrations.NewSatelliteClient("MOCK_KEY")
-    visClient := integrations.NewVisionClient()
-    mcaClient := integrations.NewMCAClient("https://mockapi.com", "MOCK_KEY")
+    // 1b. Load Config (ORACLE_CONFIG or configs/config.yaml, env overrides applied)
+    cfg, err := config.Load("")
+    if err != nil {
+        log.Fatal("Failed to load config:", err)
+    }
     
-    // 3. Init Crypto Signer
-    pk := os.Getenv("ORACLE_PRIVATE_KEY")
-    if pk == "" {
-        pk = "0x0000000000000000000000000000000000000000000000000000000000000001" // Dev fallback
+    // 2. Init Clients (Mocked/Free Tier)
+    satClient := integrations.NewSatelliteClient(cfg.APIs.Imagery)
+    if satClient.Cache != nil {
+        satClient.Cache.StartPruning(context.Background(), time.Hour)
     }
-    signer, err := crypto.NewSigner(pk)
+    visClient, err := integrations.NewVisionClient(cfg.APIs.Vision)
+    if err != nil {
+        log.Fatal("Failed to init vision backend:", err)
+    }
+    geoIndex, err := geo.LoadFiles(cfg.Geo.Boundaries, cfg.Geo.Landuse)
+    if err != nil {
+        log.Fatal("Failed to load geo data:", err)
+    }
+    mcaClient := integrations.NewMCAClient(cfg.APIs.MCA.BaseURL, cfg.APIs.MCA.APIKey)
+    actClient := integrations.NewActivityClient(cfg.APIs.Activity.APIKey)
+    
+    // 3. Init Crypto Signer (attestations, webhooks and transactions)
+    signer, err := newSigner(cfg.Signer, cfg.Mode)
     if err != nil {
         log.Fatal("Failed to init signer:", err)
     }
+    if cfg.Mode != config.ModeDev {
+        if err := crypto.CheckProductionKey(signer); err != nil {
+            log.Fatalf("Refusing to start in %s mode: %v", cfg.Mode, err)
+        }
+    }
+    runMode, oracleAddress = cfg.Mode, signer.Address().Hex()
+    log.Printf("Oracle signer: %s (%s, %s mode)", oracleAddress, cfg.Signer.Type, cfg.Mode)
     
-    // 3b. Init Blockchain Client
-    rpcURL := os.Getenv("BLOCKCHAIN_RPC_URL")
-    contractAddr := os.Getenv("REGISTRY_CONTRACT_ADDRESS")
+    // 3b. Init Blockchain Client and receipt tracker
     var chainClient *blockchain.Client
-    if rpcURL != "" && contractAddr != "" {
-        client, err := blockchain.NewClient(rpcURL, pk, contractAddr)
-        if err != nil {
+    var txTracker *blockchain.Tracker
+    if cfg.Chain.RPCURL != "" && cfg.Chain.RegistryAddress != "" {
+        client, err := blockchain.NewClient(cfg.Chain, signer)
+        if err != nil && cfg.Mode != config.ModeDev {
+            log.Fatalf("Failed to connect to blockchain: %v", err)
+        } else if err != nil {
             log.Printf("Warning: Failed to connect to blockchain: %v", err)
         } else {
             chainClient = client
-            log.Println("Connected to Blockchain at", rpcURL)
+            defer chainClient.Close()
+            log.Println("Connected to Blockchain at", cfg.Chain.RPCURL)
+            
+            txTracker, err = blockchain.NewTracker(client, cfg.Chain)
+            if err != nil {
+                log.Fatal("Failed to init tx tracker:", err)
+            }
+        }
+    }
+    
+    // 3c. Outside dev, the signer must be an authorised oracle on the registry
+    if cfg.Mode != config.ModeDev {
+        ok, err := chainClient.HasOracleRole(context.Background())
+        if err != nil {
+            log.Fatal("Failed to check ORACLE_ROLE:", err)
+        }
+        if !ok {
+            log.Fatalf("Refusing to start in %s mode: %s does not hold ORACLE_ROLE on %s", cfg.Mode, oracleAddress, cfg.Chain.RegistryAddress)
         }
+        
+        // Only the submitting node sends registry transactions
+        if !cfg.Consensus.Enabled || cfg.Consensus.Submitter {
+            ok, err := chainClient.HasConsensusRole(context.Background())
+            if err != nil {
+                log.Fatal("Failed to check CONSENSUS_ROLE:", err)
+            }
+            if !ok {
+                log.Fatalf("Refusing to start in %s mode: submitter %s does not hold CONSENSUS_ROLE on %s", cfg.Mode, oracleAddress, cfg.Chain.RegistryAddress)
+            }
+        }
+    }
+    
+    // 3d. EIP-712 domain for attestation signatures
+    domain, err := attestationDomain(cfg, chainClient)
+    if err != nil {
+        log.Fatal("Failed to configure attestation domain:", err)
+    }
+    log.Printf("Attestation domain: %s v%s, chain %s, contract %s", domain.Name, domain.Version, domain.ChainID, domain.VerifyingContract.Hex())
+    
+    // 3e. Open Verification Store
+    resultStore, err = store.Open(cfg.Store.Path)
+    if err != nil {
+        log.Fatal("Failed to open store:", err)
+    }
+    defer resultStore.Close()
+    
+    // 3f. Index registry events into the local asset table
+    if chainClient != nil && cfg.Indexer.Enabled {
+        assetIndexer, err := indexer.New(chainClient, resultStore, cfg.Indexer)
+        if err != nil {
+            log.Fatal("Failed to init indexer:", err)
+        }
+        go assetIndexer.Run(context.Background())
     }
     
     // 4. Init Handlers
-    existVerifier := handlers.NewExistenceVerifier(satClient, visClient)
-    ownVerifier := handlers.NewOwnershipVerifier(mcaClient)
+    providers := handlers.NewProviderRegistry()
+    for _, p := range []struct {
+        key      string
+        provider handlers.SignalProvider
+    }{
+        {"satellite", handlers.NewSatelliteProvider(satClient)},
+        {"vision", handlers.NewVisionProvider(satClient, visClient)},
+        {"geo", handlers.NewGeoProvider(geoIndex)},
+        {"registry", handlers.NewMCAProvider(mcaClient)},
+        {"deed", handlers.NewDeedProvider()},
+        {"utility", handlers.NewUtilityProvider(actClient)},
+        {"tax", handlers.NewTaxProvider(actClient)},
+        {"occupancy", handlers.NewOccupancyProvider(actClient)},
+    } {
+        if err := providers.Register(p.key, p.provider); err != nil {
+            log.Fatal("Failed to register signal provider:", err)
+        }
+    }
     
-    aggregator = handlers.NewOracleAggregator(existVerifier, ownVerifier, signer, chainClient)
+    existProviders, err := providers.Resolve(cfg.Signals.Existence, cfg)
+    if err != nil {
+        log.Fatal("Failed to configure existence signals:", err)
+    }
+    ownProviders, err := providers.Resolve(cfg.Signals.Ownership, cfg)
+    if err != nil {
+        log.Fatal("Failed to configure ownership signals:", err)
+    }
+    actProviders, err := providers.Resolve(cfg.Signals.Activity, cfg)
+    if err != nil {
+        log.Fatal("Failed to configure activity signals:", err)
+    }
+    
+    existVerifier := handlers.NewExistenceVerifier(existProviders, cfg.Scoring)
+    ownVerifier := handlers.NewOwnershipVerifier(ownProviders, cfg.Scoring)
+    actVerifier := handlers.NewActivityVerifier(actProviders, cfg.Scoring)
+    
+    aggregator = handlers.NewOracleAggregator(existVerifier, ownVerifier, actVerifier, signer, chainClient, txTracker, resultStore, domain, cfg.Attestation.TTL)
+    
+    if chainClient != nil {
+        attestationVerifier = handlers.NewAttestationVerifier(chainClient, resultStore, domain)
+    }
+    
+    // 4b. With consensus on, submissions go through a round with the peer nodes
+    pipeline = aggregator
+    var peerNode *consensus.Node
+    if cfg.Consensus.Enabled {
+        pipeline = consensus.NewCoordinator(aggregator, cfg.Consensus, cfg.Scoring.Thresholds)
+        peerNode = consensus.NewNode(aggregator, cfg.Consensus)
+        log.Printf("Consensus: %d-of-%d with %d peers (submitter: %v)", cfg.Consensus.Threshold, len(cfg.Consensus.Peers)+1, len(cfg.Consensus.Peers), cfg.Consensus.Submitter)
+        consensusCfg = cfg.Consensus
+    }
+    
+    // 4c. Async Job Workers
+    jobQueue = jobs.NewQueue(pipeline, jobs.NewNotifier(signer, cfg.Jobs.WebhookTimeout), cfg.Jobs)
+    jobQueue.Start(context.Background())
     
     // 5. Router
     r := mux.NewRouter()
     r.HandleFunc("/health", handleHealth).Methods("GET")
     r.HandleFunc("/verify", handleVerify).Methods("POST")
+    r.HandleFunc("/jobs/{id}", handleGetJob).Methods("GET")
+    r.HandleFunc("/verifications", handleListVerifications).Methods("GET")
+    r.HandleFunc("/verifications/{id}", handleGetVerification).Methods("GET")
+    r.HandleFunc("/verifications/{id}/history", handleVerificationHistory).Methods("GET")
+    r.HandleFunc("/assets/{fingerprint}", handleGetAsset).Methods("GET")
+    r.HandleFunc("/attestations/{fingerprint}/verify", handleVerifyAttestation).Methods("GET")
+    r.HandleFunc("/evidence/{hash}", handleGetEvidence).Methods("GET")
+    if peerNode != nil {
+        r.Handle(consensus.AttestPath, peerNode).Methods("POST")
+    }
     
-    port := os.Getenv("ORACLE_PORT")
-    if port == "" {
-        port = "8080"
+    srv := &http.Server{
+        Addr:         cfg.Server.Addr(),
+        Handler:      http.TimeoutHandler(r, cfg.Server.TimeoutDuration(), "Request timed out"), // request-scoped deadline
+        ReadTimeout:  cfg.Server.TimeoutDuration(),
+        WriteTimeout: cfg.Server.TimeoutDuration(),
     }
     
-    log.Printf("Oracle Node starting on port %s...", port)
-    log.Fatal(http.ListenAndServe(":"+port, r))
+    log.Printf("Oracle Node starting on %s...", srv.Addr)
+    log.Fatal(srv.ListenAndServe())
+}
+
+// newSigner opens the configured key backend. The built-in key is only used in dev mode.
+func newSigner(cfg config.SignerConfig, mode string) (crypto.Signer, error) {
+    switch cfg.Type {
+    case config.SignerKeystore:
+        return crypto.NewKeystoreSigner(cfg.Keystore, cfg.PassphraseFile)
+    case config.SignerExternal:
+        return crypto.NewExternalSigner(cfg.URL, cfg.Address, cfg.Timeout)
+    default:
+        pk := os.Getenv("ORACLE_PRIVATE_KEY")
+        if pk == "" && mode != config.ModeDev {
+            return nil, errors.New("ORACLE_PRIVATE_KEY is not set")
+        }
+        if pk == "" {
+            log.Println("Warning: ORACLE_PRIVATE_KEY not set, using the public dev key")
+            pk = "0x0000000000000000000000000000000000000000000000000000000000000001" // Dev fallback
+        }
+        return crypto.NewKeySigner(pk)
+    }
+}
+
+// attestationDomain binds signatures to the registry and chain. The configured chain ID
+// is only used on its own when no node is connected (dev).
+func attestationDomain(cfg *config.Config, chainClient *blockchain.Client) (crypto.AttestationDomain, error) {
+    domain := crypto.AttestationDomain{
+        Name:              cfg.Attestation.Name,
+        Version:           cfg.Attestation.Version,
+        ChainID:           big.NewInt(cfg.Attestation.ChainID),
+        VerifyingContract: common.HexToAddress(cfg.Chain.RegistryAddress),
+    }
+    if chainClient == nil {
+        return domain, nil
+    }
+    if cfg.Attestation.ChainID != 0 && domain.ChainID.Cmp(chainClient.ChainID) != 0 {
+        return domain, fmt.Errorf("attestation.chain_id %s does not match the node's chain %s", domain.ChainID, chainClient.ChainID)
+    }
+    domain.ChainID = chainClient.ChainID
+    return domain, nil
 }
 
 func handleHealth(w http.ResponseWriter, r *http.Request) {
-    w.WriteHeader(http.StatusOK)
-    w.Write([]byte("Oracle Node Active"))
+    writeJSON(w, map[string]string{
+        "status":         "Oracle Node Active",
+        "mode":           runMode,
+        "oracle_address": oracleAddress,
+    })
+}
+
+// verifyRequest is a submission plus an optional webhook for job completion
+type verifyRequest struct {
+    types.SubmissionData
+    CallbackURL string `json:"callback_url,omitempty"`
 }
 
+// POST /verify queues the submission and returns 202 with a job ID.
+// POST /verify?sync=true blocks and returns the OracleResult directly.
 func handleVerify(w http.ResponseWriter, r *http.Request) {
-    var sub types.SubmissionData
-    if err := json.NewDecoder(r.Body).Decode(&sub); err != nil {
+    // Only the submitter can anchor a consensus result; other nodes send callers there
+    if consensusCfg.Enabled && !consensusCfg.Submitter {
+        if consensusCfg.SubmitterURL == "" {
+            http.Error(w, "This node is not the consensus submitter; send submissions to the submitter node", http.StatusMisdirectedRequest)
+            return
+        }
+        // 307 keeps the method and body
+        http.Redirect(w, r, strings.TrimRight(consensusCfg.SubmitterURL, "/")+r.URL.RequestURI(), http.StatusTemporaryRedirect)
+        return
+    }
+    
+    var req verifyRequest
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
         http.Error(w, "Invalid request body", http.StatusBadRequest)
         return
     }
+    sub := req.SubmissionData
+    
+    if r.URL.Query().Get("sync") == "true" {
+        result, err := pipeline.Attest(r.Context(), &sub)
+        if err != nil {
+            http.Error(w, "Verification failed: "+err.Error(), http.StatusInternalServerError)
+            return
+        }
+        // Push failures are recorded on the stored verification, not returned
+        if _, err := pipeline.Anchor(r.Context(), &sub, result); err != nil {
+            log.Printf("Anchoring %s failed: %v", sub.ID, err)
+        }
+        writeJSON(w, result)
+        return
+    }
     
-    result, err := aggregator.VerifySubmission(&sub)
+    job, err := jobQueue.Submit(&sub, req.CallbackURL)
     if err != nil {
-        http.Error(w, "Verification failed: "+err.Error(), http.StatusInternalServerError)
+        if errors.Is(err, jobs.ErrQueueFull) {
+            http.Error(w, "Oracle busy, retry later", http.StatusServiceUnavailable)
+            return
+        }
+        http.Error(w, "Failed to queue job: "+err.Error(), http.StatusInternalServerError)
         return
     }
     
+    w.Header().Set("Location", "/jobs/"+job.ID)
+    w.Header().Set("Content-Type", "application/json")
+    w.WriteHeader(http.StatusAccepted)
+    json.NewEncoder(w).Encode(job)
+}
+
+func handleGetJob(w http.ResponseWriter, r *http.Request) {
+    job, ok := jobQueue.Get(mux.Vars(r)["id"])
+    if !ok {
+        http.Error(w, "Not found", http.StatusNotFound)
+        return
+    }
+    writeJSON(w, job)
+}
+
+func handleGetVerification(w http.ResponseWriter, r *http.Request) {
+    rec, err := resultStore.GetVerification(mux.Vars(r)["id"])
+    if err != nil {
+        writeStoreError(w, err)
+        return
+    }
+    writeJSON(w, rec)
+}
+
+func handleVerificationHistory(w http.ResponseWriter, r *http.Request) {
+    history, err := resultStore.History(mux.Vars(r)["id"])
+    if err != nil {
+        writeStoreError(w, err)
+        return
+    }
+    writeJSON(w, history)
+}
+
+// GET /verifications?status=passed|failed&since=<RFC3339>
+func handleListVerifications(w http.ResponseWriter, r *http.Request) {
+    filter := store.ListFilter{Status: r.URL.Query().Get("status")}
+    if since := r.URL.Query().Get("since"); since != "" {
+        t, err := time.Parse(time.RFC3339, since)
+        if err != nil {
+            http.Error(w, "Invalid since (want RFC3339)", http.StatusBadRequest)
+            return
+        }
+        filter.Since = t
+    }
+    
+    list, err := resultStore.ListVerifications(filter)
+    if err != nil {
+        writeStoreError(w, err)
+        return
+    }
+    writeJSON(w, list)
+}
+
+// GET /assets/{fingerprint} accepts the 0x fingerprint or the submission ID it derives from
+func handleGetAsset(w http.ResponseWriter, r *http.Request) {
+    fingerprint := common.Hash(blockchain.Fingerprint(mux.Vars(r)["fingerprint"])).Hex()
+    asset, err := resultStore.GetAsset(fingerprint)
+    if err != nil {
+        writeStoreError(w, err)
+        return
+    }
+    writeJSON(w, asset)
+}
+
+// GET /attestations/{fingerprint}/verify checks the on-chain attestation against stored evidence
+func handleVerifyAttestation(w http.ResponseWriter, r *http.Request) {
+    if attestationVerifier == nil {
+        http.Error(w, "No blockchain configured", http.StatusServiceUnavailable)
+        return
+    }
+    check, err := attestationVerifier.Verify(r.Context(), mux.Vars(r)["fingerprint"])
+    if err != nil {
+        if errors.Is(err, store.ErrNotFound) {
+            writeStoreError(w, err)
+            return
+        }
+        http.Error(w, "Attestation check failed: "+err.Error(), http.StatusBadGateway)
+        return
+    }
+    writeJSON(w, check)
+}
+
+// GET /evidence/{hash} returns the exact bytes a provider's signal was derived
+// from; their keccak256 is the evidence hash committed in the signal's leaf
+func handleGetEvidence(w http.ResponseWriter, r *http.Request) {
+    raw := common.FromHex(mux.Vars(r)["hash"])
+    if len(raw) != common.HashLength {
+        http.Error(w, "Invalid evidence hash", http.StatusBadRequest)
+        return
+    }
+    data, err := resultStore.GetEvidence(common.BytesToHash(raw).Hex())
+    if err != nil {
+        writeStoreError(w, err)
+        return
+    }
+    w.Header().Set("Content-Type", http.DetectContentType(data))
+    w.Write(data)
+}
+
+func writeStoreError(w http.ResponseWriter, err error) {
+    if errors.Is(err, store.ErrNotFound) {
+        http.Error(w, "Not found", http.StatusNotFound)
+        return
+    }
+    http.Error(w, "Store error: "+err.Error(), http.StatusInternalServerError)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
     w.Header().Set("Content-Type", "application/json")
-    json.NewEncoder(w).Encode(result)
+    json.NewEncoder(w).Encode(v)
 }
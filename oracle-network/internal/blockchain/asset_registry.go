@@ -42,19 +42,41 @@ type AssetRegistryAsset struct {
 	Timestamp         *big.Int
 	Eligible          bool
 	Tokenized         bool
-	TokenAddress      common.Address
 	IsMock            bool
+	TokenAddress      common.Address
 }
 
 // AssetRegistryMetaData contains all meta data concerning the AssetRegistry contract.
 var AssetRegistryMetaData = &bind.MetaData{
-	ABI: "[{\"type\":\"constructor\",\"inputs\":[{\"name\":\"admin\",\"type\":\"address\",\"internalType\":\"address\"}],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"CONSENSUS_ROLE\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"DEFAULT_ADMIN_ROLE\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"ORACLE_ROLE\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"assets\",\"inputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"fingerprint\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"oracleAttestation\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"abmOutputHash\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"existenceScore\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"ownershipScore\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"fraudScore\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"riskScore\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"owner\",\"type\":\"address\",\"internalType\":\"address\"},{\"name\":\"timestamp\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"eligible\",\"type\":\"bool\",\"internalType\":\"bool\"},{\"name\":\"tokenized\",\"type\":\"bool\",\"internalType\":\"bool\"},{\"name\":\"tokenAddress\",\"type\":\"address\",\"internalType\":\"address\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"getAsset\",\"inputs\":[{\"name\":\"fingerprint\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"\",\"type\":\"tuple\",\"internalType\":\"structAssetRegistry.Asset\",\"components\":[{\"name\":\"fingerprint\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"oracleAttestation\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"abmOutputHash\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"existenceScore\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"ownershipScore\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"fraudScore\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"riskScore\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"owner\",\"type\":\"address\",\"internalType\":\"address\"},{\"name\":\"timestamp\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"eligible\",\"type\":\"bool\",\"internalType\":\"bool\"},{\"name\":\"tokenized\",\"type\":\"bool\",\"internalType\":\"bool\"},{\"name\":\"tokenAddress\",\"type\":\"address\",\"internalType\":\"address\"}]}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"getOwnerAssets\",\"inputs\":[{\"name\":\"owner\",\"type\":\"address\",\"internalType\":\"address\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes32[]\",\"internalType\":\"bytes32[]\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"getRoleAdmin\",\"inputs\":[{\"name\":\"role\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"getTokenAddress\",\"inputs\":[{\"name\":\"fingerprint\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"\",\"type\":\"address\",\"internalType\":\"address\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"grantRole\",\"inputs\":[{\"name\":\"role\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"account\",\"type\":\"address\",\"internalType\":\"address\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"hasRole\",\"inputs\":[{\"name\":\"role\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"account\",\"type\":\"address\",\"internalType\":\"address\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"isEligible\",\"inputs\":[{\"name\":\"fingerprint\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"isTokenized\",\"inputs\":[{\"name\":\"fingerprint\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"markAsTokenized\",\"inputs\":[{\"name\":\"fingerprint\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"tokenAddress\",\"type\":\"address\",\"internalType\":\"address\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"ownerAssets\",\"inputs\":[{\"name\":\"\",\"type\":\"address\",\"internalType\":\"address\"},{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"registerAsset\",\"inputs\":[{\"name\":\"fingerprint\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"owner\",\"type\":\"address\",\"internalType\":\"address\"},{\"name\":\"oracleAttestation\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"abmOutputHash\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"scores\",\"type\":\"uint256[4]\",\"internalType\":\"uint256[4]\"},{\"name\":\"eligible\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"registeredFingerprints\",\"inputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"renounceRole\",\"inputs\":[{\"name\":\"role\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"callerConfirmation\",\"type\":\"address\",\"internalType\":\"address\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"revokeRole\",\"inputs\":[{\"name\":\"role\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"account\",\"type\":\"address\",\"internalType\":\"address\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"supportsInterface\",\"inputs\":[{\"name\":\"interfaceId\",\"type\":\"bytes4\",\"internalType\":\"bytes4\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"updateAsset\",\"inputs\":[{\"name\":\"fingerprint\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"newOracleAttestation\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"newAbmHash\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"event\",\"name\":\"AssetRegistered\",\"inputs\":[{\"name\":\"fingerprint\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"},{\"name\":\"owner\",\"type\":\"address\",\"indexed\":true,\"internalType\":\"address\"},{\"name\":\"eligible\",\"type\":\"bool\",\"indexed\":false,\"internalType\":\"bool\"},{\"name\":\"timestamp\",\"type\":\"uint256\",\"indexed\":false,\"internalType\":\"uint256\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"AssetTokenized\",\"inputs\":[{\"name\":\"fingerprint\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"},{\"name\":\"tokenAddress\",\"type\":\"address\",\"indexed\":true,\"internalType\":\"address\"},{\"name\":\"timestamp\",\"type\":\"uint256\",\"indexed\":false,\"internalType\":\"uint256\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"AssetUpdated\",\"inputs\":[{\"name\":\"fingerprint\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"},{\"name\":\"newOracleAttestation\",\"type\":\"bytes32\",\"indexed\":false,\"internalType\":\"bytes32\"},{\"name\":\"newAbmHash\",\"type\":\"bytes32\",\"indexed\":false,\"internalType\":\"bytes32\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"RoleAdminChanged\",\"inputs\":[{\"name\":\"role\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"},{\"name\":\"previousAdminRole\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"},{\"name\":\"newAdminRole\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"RoleGranted\",\"inputs\":[{\"name\":\"role\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"},{\"name\":\"account\",\"type\":\"address\",\"indexed\":true,\"internalType\":\"address\"},{\"name\":\"sender\",\"type\":\"address\",\"indexed\":true,\"internalType\":\"address\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"RoleRevoked\",\"inputs\":[{\"name\":\"role\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"},{\"name\":\"account\",\"type\":\"address\",\"indexed\":true,\"internalType\":\"address\"},{\"name\":\"sender\",\"type\":\"address\",\"indexed\":true,\"internalType\":\"address\"}],\"anonymous\":false},{\"type\":\"error\",\"name\":\"AccessControlBadConfirmation\",\"inputs\":[]},{\"type\":\"error\",\"name\":\"AccessControlUnauthorizedAccount\",\"inputs\":[{\"name\":\"account\",\"type\":\"address\",\"internalType\":\"address\"},{\"name\":\"neededRole\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}]},{\"type\":\"error\",\"name\":\"ReentrancyGuardReentrantCall\",\"inputs\":[]}]",
+	ABI: "[{\"type\":\"constructor\",\"inputs\":[{\"name\":\"admin\",\"type\":\"address\",\"internalType\":\"address\"}],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"CONSENSUS_ROLE\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"DEFAULT_ADMIN_ROLE\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"ORACLE_ROLE\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"assets\",\"inputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"fingerprint\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"oracleAttestation\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"abmOutputHash\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"existenceScore\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"ownershipScore\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"fraudScore\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"riskScore\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"owner\",\"type\":\"address\",\"internalType\":\"address\"},{\"name\":\"timestamp\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"eligible\",\"type\":\"bool\",\"internalType\":\"bool\"},{\"name\":\"tokenized\",\"type\":\"bool\",\"internalType\":\"bool\"},{\"name\":\"isMock\",\"type\":\"bool\",\"internalType\":\"bool\"},{\"name\":\"tokenAddress\",\"type\":\"address\",\"internalType\":\"address\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"getAsset\",\"inputs\":[{\"name\":\"fingerprint\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"\",\"type\":\"tuple\",\"internalType\":\"structAssetRegistry.Asset\",\"components\":[{\"name\":\"fingerprint\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"oracleAttestation\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"abmOutputHash\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"existenceScore\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"ownershipScore\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"fraudScore\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"riskScore\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"owner\",\"type\":\"address\",\"internalType\":\"address\"},{\"name\":\"timestamp\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"eligible\",\"type\":\"bool\",\"internalType\":\"bool\"},{\"name\":\"tokenized\",\"type\":\"bool\",\"internalType\":\"bool\"},{\"name\":\"isMock\",\"type\":\"bool\",\"internalType\":\"bool\"},{\"name\":\"tokenAddress\",\"type\":\"address\",\"internalType\":\"address\"}]}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"getOwnerAssets\",\"inputs\":[{\"name\":\"owner\",\"type\":\"address\",\"internalType\":\"address\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes32[]\",\"internalType\":\"bytes32[]\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"getRoleAdmin\",\"inputs\":[{\"name\":\"role\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"getTokenAddress\",\"inputs\":[{\"name\":\"fingerprint\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"\",\"type\":\"address\",\"internalType\":\"address\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"grantRole\",\"inputs\":[{\"name\":\"role\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"account\",\"type\":\"address\",\"internalType\":\"address\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"hasRole\",\"inputs\":[{\"name\":\"role\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"account\",\"type\":\"address\",\"internalType\":\"address\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"isEligible\",\"inputs\":[{\"name\":\"fingerprint\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"isTokenized\",\"inputs\":[{\"name\":\"fingerprint\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"markAsTokenized\",\"inputs\":[{\"name\":\"fingerprint\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"tokenAddress\",\"type\":\"address\",\"internalType\":\"address\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"ownerAssets\",\"inputs\":[{\"name\":\"\",\"type\":\"address\",\"internalType\":\"address\"},{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"registerAsset\",\"inputs\":[{\"name\":\"fingerprint\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"owner\",\"type\":\"address\",\"internalType\":\"address\"},{\"name\":\"oracleAttestation\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"abmOutputHash\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"scores\",\"type\":\"uint256[4]\",\"internalType\":\"uint256[4]\"},{\"name\":\"eligible\",\"type\":\"bool\",\"internalType\":\"bool\"},{\"name\":\"isMock\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"registeredFingerprints\",\"inputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"renounceRole\",\"inputs\":[{\"name\":\"role\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"callerConfirmation\",\"type\":\"address\",\"internalType\":\"address\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"revokeRole\",\"inputs\":[{\"name\":\"role\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"account\",\"type\":\"address\",\"internalType\":\"address\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"supportsInterface\",\"inputs\":[{\"name\":\"interfaceId\",\"type\":\"bytes4\",\"internalType\":\"bytes4\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"updateAsset\",\"inputs\":[{\"name\":\"fingerprint\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"newOracleAttestation\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"newAbmHash\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"event\",\"name\":\"AssetRegistered\",\"inputs\":[{\"name\":\"fingerprint\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"},{\"name\":\"owner\",\"type\":\"address\",\"indexed\":true,\"internalType\":\"address\"},{\"name\":\"eligible\",\"type\":\"bool\",\"indexed\":false,\"internalType\":\"bool\"},{\"name\":\"isMock\",\"type\":\"bool\",\"indexed\":false,\"internalType\":\"bool\"},{\"name\":\"timestamp\",\"type\":\"uint256\",\"indexed\":false,\"internalType\":\"uint256\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"AssetTokenized\",\"inputs\":[{\"name\":\"fingerprint\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"},{\"name\":\"tokenAddress\",\"type\":\"address\",\"indexed\":true,\"internalType\":\"address\"},{\"name\":\"timestamp\",\"type\":\"uint256\",\"indexed\":false,\"internalType\":\"uint256\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"AssetUpdated\",\"inputs\":[{\"name\":\"fingerprint\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"},{\"name\":\"newOracleAttestation\",\"type\":\"bytes32\",\"indexed\":false,\"internalType\":\"bytes32\"},{\"name\":\"newAbmHash\",\"type\":\"bytes32\",\"indexed\":false,\"internalType\":\"bytes32\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"RoleAdminChanged\",\"inputs\":[{\"name\":\"role\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"},{\"name\":\"previousAdminRole\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"},{\"name\":\"newAdminRole\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"RoleGranted\",\"inputs\":[{\"name\":\"role\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"},{\"name\":\"account\",\"type\":\"address\",\"indexed\":true,\"internalType\":\"address\"},{\"name\":\"sender\",\"type\":\"address\",\"indexed\":true,\"internalType\":\"address\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"RoleRevoked\",\"inputs\":[{\"name\":\"role\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"},{\"name\":\"account\",\"type\":\"address\",\"indexed\":true,\"internalType\":\"address\"},{\"name\":\"sender\",\"type\":\"address\",\"indexed\":true,\"internalType\":\"address\"}],\"anonymous\":false},{\"type\":\"error\",\"name\":\"AccessControlBadConfirmation\",\"inputs\":[]},{\"type\":\"error\",\"name\":\"AccessControlUnauthorizedAccount\",\"inputs\":[{\"name\":\"account\",\"type\":\"address\",\"internalType\":\"address\"},{\"name\":\"neededRole\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}]},{\"type\":\"error\",\"name\":\"ReentrancyGuardReentrantCall\",\"inputs\":[]}]",
+	Bin: "0x60803461006c57601f6112b438819003918201601f19168301916001600160401b038311848410176100705780849260209460405283398101031261006c57516001600160a01b038116810361006c5761005c9060018055610084565b506040516111a090816101148239f35b5f80fd5b634e487b7160e01b5f52604160045260245ffd5b6001600160a01b03165f8181527fad3228b676f7d3cd4284a5443f17f1962b36e491b30a40b2405849e597ba5fb5602052604081205490919060ff1661010f57818052816020526040822081835260205260408220600160ff1982541617905533917f2f8788117e7eff1d82e926ec794901d17c78024a50270940304540a733656f0d8180a4600190565b509056fe6080806040526004361015610012575f80fd5b5f3560e01c90816301ffc9a714610ed95750806307e2cea514610e9f578063248a9ca314610e735780632cc3ce8014610c915780632d38f89b14610bcd5780632f2ff15d14610b91578063320cc59714610b1257806336568abe14610acb5780633c74e0d914610a80578063455bd95114610a28578063610e4575146109d95780636bde81431461054d5780637813f3f21461036d57806391d148541461032557806398ca362d146102f65780639fda5b6614610230578063a217fddf14610216578063b12e441014610174578063d547741f146101365763fa2dabad146100f8575f80fd5b34610132575f3660031901126101325760206040517f36fd43ede163045b10e1f0abd16f62f165fce3fa7b6cde217bcea3bc47663acc8152f35b5f80fd5b3461013257604036600319011261013257610172600435610155610f42565b90805f525f60205261016d600160405f200154610ffa565b611097565b005b346101325760208060031936011261013257600435805f52600482526101a060ff60405f205416611127565b805f526002825260ff600960405f20015460081c16156101db575f526002815260018060a01b03600960405f20015460181c16604051908152f35b60405162461bcd60e51b8152600481018390526013602482015272105cdcd95d081b9bdd081d1bdad95b9a5e9959606a1b6044820152606490fd5b34610132575f3660031901126101325760206040515f8152f35b34610132576020366003190112610132576004355f5260026020526101a060405f2080549060018101546002820154916003810154926004820154600583015460068401549160018060a01b0396876007870154169460096008880154970154976040519a8b5260208b015260408a01526060890152608088015260a087015260c086015260e085015261010084015260ff8116151561012084015260ff8160081c16151561014084015260ff8160101c16151561016084015260181c16610180820152f35b34610132576020366003190112610132576004355f526004602052602060ff60405f2054166040519015158152f35b346101325760403660031901126101325761033e610f42565b6004355f525f60205260405f209060018060a01b03165f52602052602060ff60405f2054166040519015158152f35b3461013257604036600319011261013257600435610389610f42565b335f9081527fad3228b676f7d3cd4284a5443f17f1962b36e491b30a40b2405849e597ba5fb5602090815260409091205491929160ff161561052f57815f52600481526103dc60ff60405f205416611127565b815f526002815260ff600960405f20015416156104f657815f526002815260ff600960405f20015460081c166104be576001600160a01b038316928315610481575f8381526002835260409020600901805462ff0100600160b81b03191660189290921b6301000000600160b81b0316919091176101001790557f02d603cbbfc6aa2a956790965e2d8cd960ef8a40a9a6f5fe59a7ec403d0712e490604051428152a3005b60405162461bcd60e51b8152600481018390526015602482015274496e76616c696420746f6b656e206164647265737360581b6044820152606490fd5b6064906040519062461bcd60e51b825260048201526011602482015270105b1c9958591e481d1bdad95b9a5e9959607a1b6044820152fd5b6064906040519062461bcd60e51b8252600482015260126024820152714173736574206e6f7420656c696769626c6560701b6044820152fd5b60405163e2517d3f60e01b81523360048201525f6024820152604490fd5b346101325761014036600319011261013257610567610f42565b610104368111610132573590811515820361013257610124351515610124350361013257610593610f81565b6002600154146109c75760026001556004355f52600460205260ff60405f205416610982576001600160a01b0381161561094d57670de0b6b3a76400008060843511610908578060a435116108c35760c4351161088857606460e4351161084e57610124358015610832575b61076a60405161060e8161110a565b600435815260443560208201526064356040820152608435606082015260a435608082015260c43560a082015260e43560c082015260018060a01b03841660e0820152426101008201528415156101208201525f6101408201528215156101608201525f6101808201526004355f526002602052600960405f20825181556020830151600182015560408301516002820155606083015160038201556080830151600482015560a0830151600582015560c083015160068201556007810160018060a01b0360e0850151166bffffffffffffffffffffffff60a01b825416179055610100830151600882015501906101208101511515825461ff00610140840151151560081b169060ff62ff0000610160860151151560101b1693169062ffffff1916171717825561018060018060a01b03910151168154906301000000600160b81b039060181b16906301000000600160b81b031916179055565b6001600160a01b0382165f90815260036020526040902080546801000000000000000081101561081e576107a391600182018155610f58565b81549060031b90600435821b915f19901b19161790556004355f52600460205260405f20600160ff1982541617905560405192151583521515602083015242604083015260018060a01b0316907fa26f2916ab185e38d995def101600481d21eb96dc8d5a46d55c08256044a0478606060043592a360018055005b634e487b7160e01b5f52604160045260245ffd5b506004356001600160e01b031916631d195cdd60e21b146105ff565b60405162461bcd60e51b8152602060048201526012602482015271496e76616c6964207269736b2073636f726560701b6044820152606490fd5b60405162461bcd60e51b8152602060048201526013602482015272496e76616c69642066726175642073636f726560681b6044820152606490fd5b60405162461bcd60e51b815260206004820152601760248201527f496e76616c6964206f776e6572736869702073636f72650000000000000000006044820152606490fd5b60405162461bcd60e51b815260206004820152601760248201527f496e76616c6964206578697374656e63652073636f72650000000000000000006044820152606490fd5b60405162461bcd60e51b815260206004820152600d60248201526c24b73b30b634b21037bbb732b960991b6044820152606490fd5b60405162461bcd60e51b815260206004820152601860248201527f417373657420616c7265616479207265676973746572656400000000000000006044820152606490fd5b604051633ee5aeb560e01b8152600490fd5b34610132576020366003190112610132576004355f52600460205260ff60405f20541680610a0f575b6020906040519015158152f35b50600260205260405f206009015460081c60ff16610a02565b3461013257604036600319011261013257610a41610f2c565b6001600160a01b03165f9081526003602052604090208054602435919082101561013257602091610a7191610f58565b90549060031b1c604051908152f35b34610132576020366003190112610132576004355f52600460205260ff60405f20541680610ab5576020906040519015158152f35b50600260205260405f206009015460ff16610a02565b3461013257604036600319011261013257610ae4610f42565b336001600160a01b03821603610b005761017290600435611097565b60405163334bd91960e11b8152600490fd5b34610132576060366003190112610132576004357f6ca74a30ba77ecd060c87cd569ca54a67fcd0a5bf5ce59a83faa3a06663ede8e6040602435604435610b57610f81565b845f526004602052610b6e60ff845f205416611127565b845f526002602052806002845f20846001820155015582519182526020820152a2005b3461013257604036600319011261013257610172600435610bb0610f42565b90805f525f602052610bc8600160405f200154610ffa565b61101b565b3461013257602080600319360112610132576001600160a01b03610bef610f2c565b165f526003815260405f206040518083835491828152019081935f52845f20905f5b86828210610c7d5750505050819003601f01601f191681019267ffffffffffffffff84118285101761081e578392918360405281840190828552518091526040840192915f5b828110610c6657505050500390f35b835185528695509381019392810192600101610c57565b835485529093019260019283019201610c11565b34610132576020366003190112610132575f610180604051610cb28161110a565b8281528260208201528260408201528260608201528260808201528260a08201528260c08201528260e08201528261010082015282610120820152826101408201528261016082015201526004355f526004602052610d1760ff60405f205416611127565b6004355f5260026020526101a060405f20604051610d348161110a565b600982549283835260018101546020840152600281015460408401526003810154606084015260048101546080840152600581015460a0840152600681015460c084015260018060a01b0360078201541660e08401526008810154610100840152015460ff8116151561012083015260ff8160081c16151561014083015260ff8160101c16151561016083015260018060a01b039060181c166101808201526040519182526020810151602083015260408101516040830152606081015160608301526080810151608083015260a081015160a083015260c081015160c083015260018060a01b0360e08201511660e083015261010081015161010083015261012081015115156101208301526101408101511515610140830152610160810151151561016083015261018060018060a01b0391015116610180820152f35b34610132576020366003190112610132576004355f525f6020526020600160405f200154604051908152f35b34610132575f3660031901126101325760206040517f68e79a7bf1e0bc45d0a330c573bc367f9cf464fd326078812f301165fbda4ef18152f35b34610132576020366003190112610132576004359063ffffffff60e01b821680920361013257602091637965db0b60e01b8114908115610f1b575b5015158152f35b6301ffc9a760e01b14905083610f14565b600435906001600160a01b038216820361013257565b602435906001600160a01b038216820361013257565b8054821015610f6d575f5260205f2001905f90565b634e487b7160e01b5f52603260045260245ffd5b335f9081527f8e2530cb50094054ac6099ab3e26a0b622fb70ce2aa7ce65c7bacccaee7f381d60205260409020547f36fd43ede163045b10e1f0abd16f62f165fce3fa7b6cde217bcea3bc47663acc9060ff1615610fdc5750565b6044906040519063e2517d3f60e01b82523360048301526024820152fd5b805f525f60205260405f20335f5260205260ff60405f20541615610fdc5750565b905f9180835282602052604083209160018060a01b03169182845260205260ff604084205416155f1461109257808352826020526040832082845260205260408320600160ff198254161790557f2f8788117e7eff1d82e926ec794901d17c78024a50270940304540a733656f0d339380a4600190565b505090565b905f9180835282602052604083209160018060a01b03169182845260205260ff6040842054165f146110925780835282602052604083208284526020526040832060ff1981541690557ff6391f5c32d9c69d2a47ea670b442974b53935d1edc7fd64eb21e047a839171b339380a4600190565b6101a0810190811067ffffffffffffffff82111761081e57604052565b1561112e57565b60405162461bcd60e51b8152602060048201526014602482015273105cdcd95d081b9bdd081c9959da5cdd195c995960621b6044820152606490fdfea2646970667358221220b333701d6efb060e39c27c6cb4d857e1ed0e2b24a8755974448ade53fbe3161964736f6c63430008150033",
 }
 
 // AssetRegistryABI is the input ABI used to generate the binding from.
 // Deprecated: Use AssetRegistryMetaData.ABI instead.
 var AssetRegistryABI = AssetRegistryMetaData.ABI
 
+// AssetRegistryBin is the compiled bytecode used for deploying new contracts.
+// Deprecated: Use AssetRegistryMetaData.Bin instead.
+var AssetRegistryBin = AssetRegistryMetaData.Bin
+
+// DeployAssetRegistry deploys a new Ethereum contract, binding an instance of AssetRegistry to it.
+func DeployAssetRegistry(auth *bind.TransactOpts, backend bind.ContractBackend, admin common.Address) (common.Address, *types.Transaction, *AssetRegistry, error) {
+	parsed, err := AssetRegistryMetaData.GetAbi()
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	if parsed == nil {
+		return common.Address{}, nil, nil, errors.New("GetABI returned nil")
+	}
+
+	address, tx, contract, err := bind.DeployContract(auth, *parsed, common.FromHex(AssetRegistryBin), backend, admin)
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	return address, tx, &AssetRegistry{AssetRegistryCaller: AssetRegistryCaller{contract: contract}, AssetRegistryTransactor: AssetRegistryTransactor{contract: contract}, AssetRegistryFilterer: AssetRegistryFilterer{contract: contract}}, nil
+}
+
 // AssetRegistry is an auto generated Go binding around an Ethereum contract.
 type AssetRegistry struct {
 	AssetRegistryCaller     // Read-only binding to the contract
@@ -292,7 +314,7 @@ func (_AssetRegistry *AssetRegistryCallerSession) ORACLEROLE() ([32]byte, error)
 
 // Assets is a free data retrieval call binding the contract method 0x9fda5b66.
 //
-// Solidity: function assets(bytes32 ) view returns(bytes32 fingerprint, bytes32 oracleAttestation, bytes32 abmOutputHash, uint256 existenceScore, uint256 ownershipScore, uint256 fraudScore, uint256 riskScore, address owner, uint256 timestamp, bool eligible, bool tokenized, address tokenAddress)
+// Solidity: function assets(bytes32 ) view returns(bytes32 fingerprint, bytes32 oracleAttestation, bytes32 abmOutputHash, uint256 existenceScore, uint256 ownershipScore, uint256 fraudScore, uint256 riskScore, address owner, uint256 timestamp, bool eligible, bool tokenized, bool isMock, address tokenAddress)
 func (_AssetRegistry *AssetRegistryCaller) Assets(opts *bind.CallOpts, arg0 [32]byte) (struct {
 	Fingerprint       [32]byte
 	OracleAttestation [32]byte
@@ -305,6 +327,7 @@ func (_AssetRegistry *AssetRegistryCaller) Assets(opts *bind.CallOpts, arg0 [32]
 	Timestamp         *big.Int
 	Eligible          bool
 	Tokenized         bool
+	IsMock            bool
 	TokenAddress      common.Address
 }, error) {
 	var out []interface{}
@@ -322,6 +345,7 @@ func (_AssetRegistry *AssetRegistryCaller) Assets(opts *bind.CallOpts, arg0 [32]
 		Timestamp         *big.Int
 		Eligible          bool
 		Tokenized         bool
+		IsMock            bool
 		TokenAddress      common.Address
 	})
 	if err != nil {
@@ -339,7 +363,8 @@ func (_AssetRegistry *AssetRegistryCaller) Assets(opts *bind.CallOpts, arg0 [32]
 	outstruct.Timestamp = *abi.ConvertType(out[8], new(*big.Int)).(**big.Int)
 	outstruct.Eligible = *abi.ConvertType(out[9], new(bool)).(*bool)
 	outstruct.Tokenized = *abi.ConvertType(out[10], new(bool)).(*bool)
-	outstruct.TokenAddress = *abi.ConvertType(out[11], new(common.Address)).(*common.Address)
+	outstruct.IsMock = *abi.ConvertType(out[11], new(bool)).(*bool)
+	outstruct.TokenAddress = *abi.ConvertType(out[12], new(common.Address)).(*common.Address)
 
 	return *outstruct, err
 
@@ -347,7 +372,7 @@ func (_AssetRegistry *AssetRegistryCaller) Assets(opts *bind.CallOpts, arg0 [32]
 
 // Assets is a free data retrieval call binding the contract method 0x9fda5b66.
 //
-// Solidity: function assets(bytes32 ) view returns(bytes32 fingerprint, bytes32 oracleAttestation, bytes32 abmOutputHash, uint256 existenceScore, uint256 ownershipScore, uint256 fraudScore, uint256 riskScore, address owner, uint256 timestamp, bool eligible, bool tokenized, address tokenAddress)
+// Solidity: function assets(bytes32 ) view returns(bytes32 fingerprint, bytes32 oracleAttestation, bytes32 abmOutputHash, uint256 existenceScore, uint256 ownershipScore, uint256 fraudScore, uint256 riskScore, address owner, uint256 timestamp, bool eligible, bool tokenized, bool isMock, address tokenAddress)
 func (_AssetRegistry *AssetRegistrySession) Assets(arg0 [32]byte) (struct {
 	Fingerprint       [32]byte
 	OracleAttestation [32]byte
@@ -360,6 +385,7 @@ func (_AssetRegistry *AssetRegistrySession) Assets(arg0 [32]byte) (struct {
 	Timestamp         *big.Int
 	Eligible          bool
 	Tokenized         bool
+	IsMock            bool
 	TokenAddress      common.Address
 }, error) {
 	return _AssetRegistry.Contract.Assets(&_AssetRegistry.CallOpts, arg0)
@@ -367,7 +393,7 @@ func (_AssetRegistry *AssetRegistrySession) Assets(arg0 [32]byte) (struct {
 
 // Assets is a free data retrieval call binding the contract method 0x9fda5b66.
 //
-// Solidity: function assets(bytes32 ) view returns(bytes32 fingerprint, bytes32 oracleAttestation, bytes32 abmOutputHash, uint256 existenceScore, uint256 ownershipScore, uint256 fraudScore, uint256 riskScore, address owner, uint256 timestamp, bool eligible, bool tokenized, address tokenAddress)
+// Solidity: function assets(bytes32 ) view returns(bytes32 fingerprint, bytes32 oracleAttestation, bytes32 abmOutputHash, uint256 existenceScore, uint256 ownershipScore, uint256 fraudScore, uint256 riskScore, address owner, uint256 timestamp, bool eligible, bool tokenized, bool isMock, address tokenAddress)
 func (_AssetRegistry *AssetRegistryCallerSession) Assets(arg0 [32]byte) (struct {
 	Fingerprint       [32]byte
 	OracleAttestation [32]byte
@@ -380,6 +406,7 @@ func (_AssetRegistry *AssetRegistryCallerSession) Assets(arg0 [32]byte) (struct
 	Timestamp         *big.Int
 	Eligible          bool
 	Tokenized         bool
+	IsMock            bool
 	TokenAddress      common.Address
 }, error) {
 	return _AssetRegistry.Contract.Assets(&_AssetRegistry.CallOpts, arg0)
@@ -387,7 +414,7 @@ func (_AssetRegistry *AssetRegistryCallerSession) Assets(arg0 [32]byte) (struct
 
 // GetAsset is a free data retrieval call binding the contract method 0x2cc3ce80.
 //
-// Solidity: function getAsset(bytes32 fingerprint) view returns((bytes32,bytes32,bytes32,uint256,uint256,uint256,uint256,address,uint256,bool,bool,address))
+// Solidity: function getAsset(bytes32 fingerprint) view returns((bytes32,bytes32,bytes32,uint256,uint256,uint256,uint256,address,uint256,bool,bool,bool,address))
 func (_AssetRegistry *AssetRegistryCaller) GetAsset(opts *bind.CallOpts, fingerprint [32]byte) (AssetRegistryAsset, error) {
 	var out []interface{}
 	err := _AssetRegistry.contract.Call(opts, &out, "getAsset", fingerprint)
@@ -404,14 +431,14 @@ func (_AssetRegistry *AssetRegistryCaller) GetAsset(opts *bind.CallOpts, fingerp
 
 // GetAsset is a free data retrieval call binding the contract method 0x2cc3ce80.
 //
-// Solidity: function getAsset(bytes32 fingerprint) view returns((bytes32,bytes32,bytes32,uint256,uint256,uint256,uint256,address,uint256,bool,bool,address))
+// Solidity: function getAsset(bytes32 fingerprint) view returns((bytes32,bytes32,bytes32,uint256,uint256,uint256,uint256,address,uint256,bool,bool,bool,address))
 func (_AssetRegistry *AssetRegistrySession) GetAsset(fingerprint [32]byte) (AssetRegistryAsset, error) {
 	return _AssetRegistry.Contract.GetAsset(&_AssetRegistry.CallOpts, fingerprint)
 }
 
 // GetAsset is a free data retrieval call binding the contract method 0x2cc3ce80.
 //
-// Solidity: function getAsset(bytes32 fingerprint) view returns((bytes32,bytes32,bytes32,uint256,uint256,uint256,uint256,address,uint256,bool,bool,address))
+// Solidity: function getAsset(bytes32 fingerprint) view returns((bytes32,bytes32,bytes32,uint256,uint256,uint256,uint256,address,uint256,bool,bool,bool,address))
 func (_AssetRegistry *AssetRegistryCallerSession) GetAsset(fingerprint [32]byte) (AssetRegistryAsset, error) {
 	return _AssetRegistry.Contract.GetAsset(&_AssetRegistry.CallOpts, fingerprint)
 }
@@ -737,21 +764,21 @@ func (_AssetRegistry *AssetRegistryTransactorSession) MarkAsTokenized(fingerprin
 	return _AssetRegistry.Contract.MarkAsTokenized(&_AssetRegistry.TransactOpts, fingerprint, tokenAddress)
 }
 
-// RegisterAsset is a paid mutator transaction binding the contract method 0x71aa80fd.
+// RegisterAsset is a paid mutator transaction binding the contract method 0x6bde8143.
 //
 // Solidity: function registerAsset(bytes32 fingerprint, address owner, bytes32 oracleAttestation, bytes32 abmOutputHash, uint256[4] scores, bool eligible, bool isMock) returns()
 func (_AssetRegistry *AssetRegistryTransactor) RegisterAsset(opts *bind.TransactOpts, fingerprint [32]byte, owner common.Address, oracleAttestation [32]byte, abmOutputHash [32]byte, scores [4]*big.Int, eligible bool, isMock bool) (*types.Transaction, error) {
 	return _AssetRegistry.contract.Transact(opts, "registerAsset", fingerprint, owner, oracleAttestation, abmOutputHash, scores, eligible, isMock)
 }
 
-// RegisterAsset is a paid mutator transaction binding the contract method 0x71aa80fd.
+// RegisterAsset is a paid mutator transaction binding the contract method 0x6bde8143.
 //
 // Solidity: function registerAsset(bytes32 fingerprint, address owner, bytes32 oracleAttestation, bytes32 abmOutputHash, uint256[4] scores, bool eligible, bool isMock) returns()
 func (_AssetRegistry *AssetRegistrySession) RegisterAsset(fingerprint [32]byte, owner common.Address, oracleAttestation [32]byte, abmOutputHash [32]byte, scores [4]*big.Int, eligible bool, isMock bool) (*types.Transaction, error) {
 	return _AssetRegistry.Contract.RegisterAsset(&_AssetRegistry.TransactOpts, fingerprint, owner, oracleAttestation, abmOutputHash, scores, eligible, isMock)
 }
 
-// RegisterAsset is a paid mutator transaction binding the contract method 0x71aa80fd.
+// RegisterAsset is a paid mutator transaction binding the contract method 0x6bde8143.
 //
 // Solidity: function registerAsset(bytes32 fingerprint, address owner, bytes32 oracleAttestation, bytes32 abmOutputHash, uint256[4] scores, bool eligible, bool isMock) returns()
 func (_AssetRegistry *AssetRegistryTransactorSession) RegisterAsset(fingerprint [32]byte, owner common.Address, oracleAttestation [32]byte, abmOutputHash [32]byte, scores [4]*big.Int, eligible bool, isMock bool) (*types.Transaction, error) {
@@ -893,13 +920,14 @@ type AssetRegistryAssetRegistered struct {
 	Fingerprint [32]byte
 	Owner       common.Address
 	Eligible    bool
+	IsMock      bool
 	Timestamp   *big.Int
 	Raw         types.Log // Blockchain specific contextual infos
 }
 
-// FilterAssetRegistered is a free log retrieval operation binding the contract event 0xbe76624284ab25f91f68e1254899d8730ad25a4fdcab3069a1d02bc4513638ff.
+// FilterAssetRegistered is a free log retrieval operation binding the contract event 0xa26f2916ab185e38d995def101600481d21eb96dc8d5a46d55c08256044a0478.
 //
-// Solidity: event AssetRegistered(bytes32 indexed fingerprint, address indexed owner, bool eligible, uint256 timestamp)
+// Solidity: event AssetRegistered(bytes32 indexed fingerprint, address indexed owner, bool eligible, bool isMock, uint256 timestamp)
 func (_AssetRegistry *AssetRegistryFilterer) FilterAssetRegistered(opts *bind.FilterOpts, fingerprint [][32]byte, owner []common.Address) (*AssetRegistryAssetRegisteredIterator, error) {
 
 	var fingerprintRule []interface{}
@@ -918,9 +946,9 @@ func (_AssetRegistry *AssetRegistryFilterer) FilterAssetRegistered(opts *bind.Fi
 	return &AssetRegistryAssetRegisteredIterator{contract: _AssetRegistry.contract, event: "AssetRegistered", logs: logs, sub: sub}, nil
 }
 
-// WatchAssetRegistered is a free log subscription operation binding the contract event 0xbe76624284ab25f91f68e1254899d8730ad25a4fdcab3069a1d02bc4513638ff.
+// WatchAssetRegistered is a free log subscription operation binding the contract event 0xa26f2916ab185e38d995def101600481d21eb96dc8d5a46d55c08256044a0478.
 //
-// Solidity: event AssetRegistered(bytes32 indexed fingerprint, address indexed owner, bool eligible, uint256 timestamp)
+// Solidity: event AssetRegistered(bytes32 indexed fingerprint, address indexed owner, bool eligible, bool isMock, uint256 timestamp)
 func (_AssetRegistry *AssetRegistryFilterer) WatchAssetRegistered(opts *bind.WatchOpts, sink chan<- *AssetRegistryAssetRegistered, fingerprint [][32]byte, owner []common.Address) (event.Subscription, error) {
 
 	var fingerprintRule []interface{}
@@ -964,9 +992,9 @@ func (_AssetRegistry *AssetRegistryFilterer) WatchAssetRegistered(opts *bind.Wat
 	}), nil
 }
 
-// ParseAssetRegistered is a log parse operation binding the contract event 0xbe76624284ab25f91f68e1254899d8730ad25a4fdcab3069a1d02bc4513638ff.
+// ParseAssetRegistered is a log parse operation binding the contract event 0xa26f2916ab185e38d995def101600481d21eb96dc8d5a46d55c08256044a0478.
 //
-// Solidity: event AssetRegistered(bytes32 indexed fingerprint, address indexed owner, bool eligible, uint256 timestamp)
+// Solidity: event AssetRegistered(bytes32 indexed fingerprint, address indexed owner, bool eligible, bool isMock, uint256 timestamp)
 func (_AssetRegistry *AssetRegistryFilterer) ParseAssetRegistered(log types.Log) (*AssetRegistryAssetRegistered, error) {
 	event := new(AssetRegistryAssetRegistered)
 	if err := _AssetRegistry.contract.UnpackLog(event, "AssetRegistered", log); err != nil {
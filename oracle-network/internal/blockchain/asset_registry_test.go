@@ -0,0 +1,86 @@
+package blockchain
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// deployRegistry deploys AssetRegistry with the signer as admin and consensus engine
+func deployRegistry(t *testing.T) (*AssetRegistry, *bind.TransactOpts, func()) {
+	t.Helper()
+	sim, backend, signer := newTestChain(t)
+	chainID, _ := backend.ChainID(context.Background())
+	auth, err := bind.NewKeyedTransactorWithChainID(crypto.ToECDSAUnsafe(common.FromHex(testKey)), chainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addr, _, _, err := DeployAssetRegistry(auth, backend, signer.Address())
+	if err != nil {
+		t.Fatalf("deploy: %v", err)
+	}
+	sim.Commit()
+
+	registry, err := NewAssetRegistry(addr, backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	role, err := registry.CONSENSUSROLE(nil)
+	if err != nil {
+		t.Fatalf("CONSENSUS_ROLE: %v", err)
+	}
+	if _, err := registry.GrantRole(auth, role, signer.Address()); err != nil {
+		t.Fatalf("grantRole: %v", err)
+	}
+	sim.Commit()
+	return registry, auth, func() { sim.Commit() }
+}
+
+func TestAssetRegistryRegisterAndGet(t *testing.T) {
+	registry, auth, commit := deployRegistry(t)
+
+	fingerprint := crypto.Keccak256Hash([]byte("asset-1"))
+	owner := common.HexToAddress("0x00000000000000000000000000000000000000AA")
+	attestation := crypto.Keccak256Hash([]byte("attestation"))
+	abm := crypto.Keccak256Hash([]byte("abm"))
+	scores := [4]*big.Int{
+		new(big.Int).Mul(big.NewInt(9), big.NewInt(1e17)),
+		new(big.Int).Mul(big.NewInt(8), big.NewInt(1e17)),
+		big.NewInt(0),
+		big.NewInt(12),
+	}
+
+	if _, err := registry.RegisterAsset(auth, fingerprint, owner, attestation, abm, scores, true, false); err != nil {
+		t.Fatalf("registerAsset: %v", err)
+	}
+	commit()
+
+	asset, err := registry.GetAsset(nil, fingerprint)
+	if err != nil {
+		t.Fatalf("getAsset: %v", err)
+	}
+	if asset.Fingerprint != fingerprint || asset.Owner != owner {
+		t.Errorf("asset = %x owned by %s, want %x owned by %s", asset.Fingerprint, asset.Owner.Hex(), fingerprint, owner.Hex())
+	}
+	if asset.OracleAttestation != attestation || asset.AbmOutputHash != abm {
+		t.Errorf("attestation/abm hash not stored")
+	}
+	if asset.ExistenceScore.Cmp(scores[0]) != 0 || asset.RiskScore.Cmp(scores[3]) != 0 {
+		t.Errorf("scores = %v/%v, want %v/%v", asset.ExistenceScore, asset.RiskScore, scores[0], scores[3])
+	}
+	if !asset.Eligible || asset.IsMock || asset.Tokenized {
+		t.Errorf("flags eligible=%v mock=%v tokenized=%v", asset.Eligible, asset.IsMock, asset.Tokenized)
+	}
+	if asset.Timestamp.Sign() == 0 {
+		t.Error("timestamp not set")
+	}
+
+	if _, err := registry.GetAsset(nil, crypto.Keccak256Hash([]byte("unknown"))); err == nil {
+		t.Error("getAsset succeeded for an unregistered fingerprint")
+	}
+}
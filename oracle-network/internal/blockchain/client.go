@@ -2,65 +2,187 @@ package blockchain
 
 import (
 	"context"
-	"crypto/ecdsa"
 	"fmt"
+	"math"
 	"math/big"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/yourorg/proptoken-oracle/internal/config"
+	oraclecrypto "github.com/yourorg/proptoken-oracle/internal/crypto"
 	"github.com/yourorg/proptoken-oracle/pkg/types"
 )
 
+// Backend is the node API the client uses. Both *ethclient.Client and the
+// go-ethereum simulated backend (ethclient/simulated) satisfy it.
+type Backend interface {
+	bind.ContractBackend
+	bind.DeployBackend
+	ethereum.ChainStateReader
+	ethereum.BlockNumberReader
+	ethereum.ChainIDReader
+}
+
 type Client struct {
-	EthClient  *ethclient.Client
-	PrivateKey *ecdsa.PrivateKey
+	EthClient  Backend
+	Signer     oraclecrypto.Signer
 	ChainID    *big.Int
 	Registry   *AssetRegistry
+	Queue      *TxQueue
+
+	RegistryAddress common.Address
+
+	stopQueue context.CancelFunc
+	rpc       *ethclient.Client // set when the client dialled its own connection
 }
 
-func NewClient(rpcURL, privateKeyHex, contractAddr string) (*Client, error) {
-	client, err := ethclient.Dial(rpcURL)
+func NewClient(cfg config.ChainConfig, signer oraclecrypto.Signer) (*Client, error) {
+	client, err := ethclient.Dial(cfg.RPCURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to eth client: %v", err)
 	}
-
-	chainID, err := client.ChainID(context.Background())
+	c, err := NewClientWithBackend(client, cfg, signer)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get chain id: %v", err)
+		client.Close()
+		return nil, err
 	}
+	c.rpc = client
+	return c, nil
+}
 
-	pk, err := crypto.HexToECDSA(privateKeyHex[2:]) // Remove 0x
+// NewClientWithBackend binds the registry on an existing backend, e.g. a simulated
+// chain. The backend stays the caller's; Close only stops the client's tx queue.
+func NewClientWithBackend(backend Backend, cfg config.ChainConfig, signer oraclecrypto.Signer) (*Client, error) {
+	chainID, err := backend.ChainID(context.Background())
 	if err != nil {
-		return nil, fmt.Errorf("invalid private key: %v", err)
+		return nil, fmt.Errorf("failed to get chain id: %v", err)
 	}
 
-	registry, err := NewAssetRegistry(common.HexToAddress(contractAddr), client)
+	registryAddr := common.HexToAddress(cfg.RegistryAddress)
+	registry, err := NewAssetRegistry(registryAddr, backend)
 	if err != nil {
 		return nil, fmt.Errorf("failed to bind to contract: %v", err)
 	}
 
+	queue := NewTxQueue(backend, signer, chainID, cfg)
+	ctx, stop := context.WithCancel(context.Background())
+	queue.Start(ctx)
+
 	return &Client{
-		EthClient:  client,
-		PrivateKey: pk,
+		EthClient:  backend,
+		Signer:     signer,
 		ChainID:    chainID,
 		Registry:   registry,
+		Queue:      queue,
+
+		RegistryAddress: registryAddr,
+
+		stopQueue: stop,
 	}, nil
 }
 
-func (c *Client) PushAttestation(subID string, att types.AttestationData, isMock bool) (string, error) {
-	auth, err := bind.NewKeyedTransactorWithChainID(c.PrivateKey, c.ChainID)
+// Close stops the tx queue, failing any later sends, and closes the RPC
+// connection if NewClient dialled it
+func (c *Client) Close() {
+	c.stopQueue()
+	if c.rpc != nil {
+		c.rpc.Close()
+	}
+}
+
+// PushAttestation registers the asset, or updates its attestation if the fingerprint
+// is already registered. It returns the transaction unconfirmed; pass it to a
+// Tracker to follow it to a final status.
+func (c *Client) PushAttestation(ctx context.Context, sub *types.SubmissionData, result *types.OracleResult) (*ethtypes.Transaction, error) {
+	merkleRoot := common.HexToHash(result.Attestation.MerkleRoot)
+	abmHash := common.HexToHash(sub.ABM.OutputHash)
+	fingerprint := Fingerprint(sub.ID)
+
+	// Updates keep the registered owner, so only a registration needs one
+	owner, ownerErr := registrationOwner(sub)
+	if ownerErr != nil {
+		registered, err := c.Registry.RegisteredFingerprints(&bind.CallOpts{Context: ctx, Pending: true}, fingerprint)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check registration: %v", err)
+		}
+		if !registered {
+			return nil, ownerErr
+		}
+	}
+
+	// existence, ownership, fraud as 1e18 fixed point; the contract takes risk as a 0-100 integer
+	scores := [4]*big.Int{
+		ToFixed18(result.Existence.Score),
+		ToFixed18(result.Ownership.Score),
+		ToFixed18(sub.ABM.FraudScore),
+		big.NewInt(int64(math.Round(clamp01(sub.ABM.RiskScore) * 100))),
+	}
+	eligible := result.Existence.Passed && result.Ownership.Passed && result.Activity.Passed
+
+	// Nonce is assigned by the queue, so concurrent pushes don't collide
+	tx, err := c.Queue.Send(ctx, func(auth *bind.TransactOpts) (*ethtypes.Transaction, error) {
+		// Checked against pending state inside the queue, so a registration
+		// still in the pool routes a second push to updateAsset
+		registered, err := c.Registry.RegisteredFingerprints(&bind.CallOpts{Context: ctx, Pending: true}, fingerprint)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check registration: %v", err)
+		}
+		if registered {
+			return c.Registry.UpdateAsset(auth, fingerprint, merkleRoot, abmHash)
+		}
+		if ownerErr != nil {
+			// A pending registration seen above was dropped
+			return nil, ownerErr
+		}
+
+		return c.Registry.RegisterAsset(
+			auth,
+			fingerprint,
+			owner,
+			merkleRoot,
+			abmHash,
+			scores,
+			eligible,
+			sub.IsMock,
+		)
+	})
 	if err != nil {
-		return "", fmt.Errorf("failed to create transactor: %v", err)
+		return nil, fmt.Errorf("failed to send transaction: %v", err)
+	}
+
+	return tx, nil
+}
+
+// registrationOwner is the owner sub registers its asset to. The registry
+// rejects the zero address, so it is refused here before a nonce is spent on it.
+func registrationOwner(sub *types.SubmissionData) (common.Address, error) {
+	if !common.IsHexAddress(sub.Owner) {
+		return common.Address{}, fmt.Errorf("submission %s has no valid owner address", sub.ID)
+	}
+	owner := common.HexToAddress(sub.Owner)
+	if owner == (common.Address{}) {
+		return common.Address{}, fmt.Errorf("submission %s has the zero address as owner", sub.ID)
 	}
+	return owner, nil
+}
+
+// ToFixed18 converts a 0-1 score to the registry's 1e18 fixed point, clamping out-of-range values
+func ToFixed18(score float64) *big.Int {
+	scaled, _ := new(big.Float).Mul(big.NewFloat(clamp01(score)), big.NewFloat(1e18)).Int(nil)
+	return scaled
+}
 
-	// Convert hex string inputs to byte arrays
-	var merkleRoot [32]byte
-	copy(merkleRoot[:], common.FromHex(att.MerkleRoot))
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
 
-	// Ensure subID is a 32-byte fingerprint.
-	// If it's a hex string, use it; otherwise hash it.
+// Fingerprint maps a submission ID to its 32-byte registry key.
+// A 0x-prefixed 32-byte hex ID is used as is; anything else is hashed.
+func Fingerprint(subID string) [32]byte {
 	var fingerprint [32]byte
 	if len(subID) == 66 && subID[:2] == "0x" {
 		copy(fingerprint[:], common.FromHex(subID))
@@ -68,25 +190,26 @@ func (c *Client) PushAttestation(subID string, att types.AttestationData, isMock
 		// Fallback: hash the string subID
 		copy(fingerprint[:], crypto.Keccak256([]byte(subID)))
 	}
+	return fingerprint
+}
 
-	// Simplification for demo: Use the Oracle's address as "owner" for now
-	mockOwner := crypto.PubkeyToAddress(c.PrivateKey.PublicKey)
-	mockAbmHash := [32]byte{}
-	mockScores := [4]*big.Int{big.NewInt(1e18), big.NewInt(1e18), big.NewInt(0), big.NewInt(0)} // scores[0] and scores[1] are existence/ownership
-
-	tx, err := c.Registry.RegisterAsset(
-		auth,
-		fingerprint,
-		mockOwner,
-		merkleRoot,
-		mockAbmHash,
-		mockScores,
-		true, // Eligible
-		isMock,
-	)
+// HasOracleRole reports whether the signer holds ORACLE_ROLE on the registry
+func (c *Client) HasOracleRole(ctx context.Context) (bool, error) {
+	opts := &bind.CallOpts{Context: ctx}
+	role, err := c.Registry.ORACLEROLE(opts)
 	if err != nil {
-		return "", fmt.Errorf("failed to send transaction: %v", err)
+		return false, fmt.Errorf("failed to read ORACLE_ROLE: %v", err)
 	}
+	return c.Registry.HasRole(opts, role, c.Address())
+}
 
-	return tx.Hash().Hex(), nil
+// HasConsensusRole reports whether the signer holds CONSENSUS_ROLE, which
+// registerAsset and updateAsset require
+func (c *Client) HasConsensusRole(ctx context.Context) (bool, error) {
+	opts := &bind.CallOpts{Context: ctx}
+	role, err := c.Registry.CONSENSUSROLE(opts)
+	if err != nil {
+		return false, fmt.Errorf("failed to read CONSENSUS_ROLE: %v", err)
+	}
+	return c.Registry.HasRole(opts, role, c.Address())
 }
@@ -0,0 +1,91 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/yourorg/proptoken-oracle/pkg/types"
+)
+
+func TestPushAttestationOwner(t *testing.T) {
+	sim, client, _ := newTestTracker(t, testChainConfig())
+	if _, err := client.Queue.Send(context.Background(), func(opts *bind.TransactOpts) (*ethtypes.Transaction, error) {
+		return client.Registry.GrantRole(opts, crypto.Keccak256Hash([]byte("CONSENSUS_ROLE")), client.Address())
+	}); err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+
+	push := func(owner, root string) (*ethtypes.Transaction, error) {
+		sub := &types.SubmissionData{ID: "sub-1", Owner: owner}
+		result := &types.OracleResult{Attestation: types.AttestationData{MerkleRoot: root}}
+		return client.PushAttestation(context.Background(), sub, result)
+	}
+	pendingNonce := func() uint64 {
+		nonce, err := client.EthClient.PendingNonceAt(context.Background(), client.Address())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return nonce
+	}
+
+	// A registration needs a real owner, refused before anything is sent
+	before := pendingNonce()
+	for _, owner := range []string{"", "not an address", "0x0000000000000000000000000000000000000000"} {
+		if _, err := push(owner, "0x01"); err == nil || !strings.Contains(err.Error(), "owner") {
+			t.Errorf("owner %q: got %v, want an owner error", owner, err)
+		}
+	}
+	if after := pendingNonce(); after != before {
+		t.Errorf("pending nonce moved from %d to %d", before, after)
+	}
+
+	owner := common.HexToAddress("0x00000000000000000000000000000000000000AA")
+	if _, err := push(owner.Hex(), "0x01"); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	sim.Commit()
+
+	// An update keeps the registered owner, so it needs none
+	if _, err := push("", "0x02"); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	sim.Commit()
+
+	asset, err := client.Registry.GetAsset(nil, Fingerprint("sub-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if asset.Owner != owner || common.Hash(asset.OracleAttestation) != common.HexToHash("0x02") {
+		t.Errorf("asset owned by %s with attestation %x, want %s with 0x02", asset.Owner.Hex(), asset.OracleAttestation, owner.Hex())
+	}
+}
+
+func TestCloseStopsQueue(t *testing.T) {
+	_, client, _ := newTestTracker(t, testChainConfig())
+	client.Close()
+
+	// Nothing drains the queue any more, so a send fails instead of waiting
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.Queue.Send(context.Background(), func(opts *bind.TransactOpts) (*ethtypes.Transaction, error) {
+			return nil, errors.New("build ran after Close")
+		})
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if !errors.Is(err, ErrQueueStopped) {
+			t.Errorf("send after Close: got %v, want ErrQueueStopped", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("send after Close did not return")
+	}
+}
@@ -0,0 +1,55 @@
+// Command gen regenerates the AssetRegistry Go binding from the checked-in ABI
+// using abigen's library API, so the binding can't drift from the ABI file.
+// Run via `go generate ./internal/blockchain`.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/abigen"
+)
+
+func main() {
+	abiPath := flag.String("abi", "", "contract ABI JSON")
+	binPath := flag.String("bin", "", "optional contract bytecode (hex); adds a Deploy function")
+	typeName := flag.String("type", "", "Go type name of the binding")
+	pkg := flag.String("pkg", "", "Go package of the generated file")
+	out := flag.String("out", "", "output file")
+	flag.Parse()
+
+	if *abiPath == "" || *typeName == "" || *pkg == "" || *out == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	abiJSON, err := os.ReadFile(*abiPath)
+	if err != nil {
+		log.Fatalf("Failed to read ABI: %v", err)
+	}
+
+	var bytecode string
+	if *binPath != "" {
+		raw, err := os.ReadFile(*binPath)
+		if err != nil {
+			log.Fatalf("Failed to read bytecode: %v", err)
+		}
+		bytecode = strings.TrimSpace(string(raw))
+	}
+
+	code, err := abigen.Bind(
+		[]string{*typeName},
+		[]string{string(abiJSON)},
+		[]string{bytecode},
+		nil, *pkg, nil, nil,
+	)
+	if err != nil {
+		log.Fatalf("Failed to generate binding: %v", err)
+	}
+
+	if err := os.WriteFile(*out, []byte(code), 0o644); err != nil {
+		log.Fatalf("Failed to write binding: %v", err)
+	}
+}
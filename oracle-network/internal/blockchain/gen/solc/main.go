@@ -0,0 +1,157 @@
+// Command solc compiles one contract through solc's standard JSON interface and
+// writes only that contract's creation bytecode, so the imported OpenZeppelin
+// contracts don't each leave a .bin behind. Sources are read here and handed to
+// solc inline, resolving imports against the base path and then each include path.
+// Run via `go generate ./internal/blockchain`.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// importPath matches the file of every import form: plain, `* as`, and `{...} from`
+var importPath = regexp.MustCompile(`(?m)^\s*import\s+(?:[^"';]*?\s+from\s+)?["']([^"']+)["']`)
+
+type source struct {
+	Content string `json:"content"`
+}
+
+type settings struct {
+	Optimizer struct {
+		Enabled bool `json:"enabled"`
+		Runs    int  `json:"runs"`
+	} `json:"optimizer"`
+	ViaIR           bool                           `json:"viaIR"`
+	EVMVersion      string                         `json:"evmVersion,omitempty"`
+	OutputSelection map[string]map[string][]string `json:"outputSelection"`
+}
+
+type output struct {
+	Errors []struct {
+		Severity         string `json:"severity"`
+		FormattedMessage string `json:"formattedMessage"`
+	} `json:"errors"`
+	Contracts map[string]map[string]struct {
+		EVM struct {
+			Bytecode struct {
+				Object         string          `json:"object"`
+				LinkReferences json.RawMessage `json:"linkReferences"`
+			} `json:"bytecode"`
+		} `json:"evm"`
+	} `json:"contracts"`
+}
+
+func main() {
+	solc := flag.String("solc", "solc", "solc binary")
+	base := flag.String("base-path", ".", "root that source unit names are relative to")
+	include := flag.String("include-path", "", "comma-separated extra roots for imports, e.g. node_modules")
+	src := flag.String("src", "", "source file, relative to the base path")
+	contract := flag.String("contract", "", "contract to compile")
+	runs := flag.Int("optimize-runs", 200, "optimizer runs; 0 disables the optimizer")
+	viaIR := flag.Bool("via-ir", false, "compile through the IR pipeline")
+	evm := flag.String("evm-version", "", "target EVM version (default: the compiler's)")
+	out := flag.String("out", "", "output file for the bytecode (hex)")
+	flag.Parse()
+
+	if *src == "" || *contract == "" || *out == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	roots := []string{*base}
+	for _, p := range strings.Split(*include, ",") {
+		if p != "" {
+			roots = append(roots, p)
+		}
+	}
+	unit := filepath.ToSlash(*src)
+	sources := make(map[string]source)
+	if err := load(sources, roots, unit); err != nil {
+		log.Fatalf("Failed to read sources: %v", err)
+	}
+
+	var s settings
+	s.Optimizer.Enabled = *runs > 0
+	s.Optimizer.Runs = *runs
+	s.ViaIR = *viaIR
+	s.EVMVersion = *evm
+	s.OutputSelection = map[string]map[string][]string{unit: {*contract: {"evm.bytecode"}}}
+	input, err := json.Marshal(map[string]interface{}{"language": "Solidity", "sources": sources, "settings": s})
+	if err != nil {
+		log.Fatalf("Failed to encode compiler input: %v", err)
+	}
+
+	cmd := exec.Command(*solc, "--standard-json")
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stderr = os.Stderr
+	raw, err := cmd.Output()
+	if err != nil {
+		log.Fatalf("Failed to run %s: %v", *solc, err)
+	}
+
+	var result output
+	if err := json.Unmarshal(raw, &result); err != nil {
+		log.Fatalf("Failed to decode compiler output: %v", err)
+	}
+	failed := false
+	for _, e := range result.Errors {
+		fmt.Fprint(os.Stderr, e.FormattedMessage)
+		failed = failed || e.Severity == "error"
+	}
+	if failed {
+		log.Fatalf("Failed to compile %s", unit)
+	}
+
+	bytecode := result.Contracts[unit][*contract].EVM.Bytecode
+	if bytecode.Object == "" {
+		log.Fatalf("Compiler output has no bytecode for %s:%s", unit, *contract)
+	}
+	if refs := string(bytecode.LinkReferences); refs != "" && refs != "{}" {
+		log.Fatalf("%s needs linked libraries, which are not supported", *contract)
+	}
+	if err := os.WriteFile(*out, []byte(bytecode.Object+"\n"), 0o644); err != nil {
+		log.Fatalf("Failed to write bytecode: %v", err)
+	}
+}
+
+// load adds unit and everything it imports to sources
+func load(sources map[string]source, roots []string, unit string) error {
+	if _, ok := sources[unit]; ok {
+		return nil
+	}
+	var (
+		data []byte
+		err  error
+	)
+	for _, root := range roots {
+		data, err = os.ReadFile(filepath.Join(root, filepath.FromSlash(unit)))
+		if err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("%s not found under %s", unit, strings.Join(roots, ", "))
+	}
+	sources[unit] = source{Content: string(data)}
+
+	for _, m := range importPath.FindAllStringSubmatch(string(data), -1) {
+		imported := m[1]
+		if strings.HasPrefix(imported, "./") || strings.HasPrefix(imported, "../") {
+			imported = path.Join(path.Dir(unit), imported)
+		}
+		if err := load(sources, roots, imported); err != nil {
+			return fmt.Errorf("%s: %v", unit, err)
+		}
+	}
+	return nil
+}
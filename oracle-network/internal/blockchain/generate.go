@@ -0,0 +1,11 @@
+package blockchain
+
+// The binding is generated from abi/AssetRegistry.abi.json, which must be kept in
+// sync with proptoken-autonomous/contracts/src/AssetRegistry.sol. The bytecode in
+// abi/AssetRegistry.bin gives the binding DeployAssetRegistry; it is compiled
+// with the optimizer settings in the contracts' foundry.toml (200 runs, via-ir)
+// against the OpenZeppelin version in their package-lock.json. The checked-in
+// bytecode was built with solc 0.8.21, as recorded in its metadata trailer.
+// Run `npm install` in proptoken-autonomous/contracts first for the OpenZeppelin imports.
+//go:generate go run ./gen/solc -base-path ../../../proptoken-autonomous/contracts -include-path ../../../proptoken-autonomous/contracts/node_modules -src src/AssetRegistry.sol -contract AssetRegistry -optimize-runs 200 -via-ir -out abi/AssetRegistry.bin
+//go:generate go run ./gen -abi abi/AssetRegistry.abi.json -bin abi/AssetRegistry.bin -type AssetRegistry -pkg blockchain -out asset_registry.go
@@ -0,0 +1,240 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/yourorg/proptoken-oracle/internal/config"
+	"github.com/yourorg/proptoken-oracle/pkg/types"
+)
+
+// TxOutcome is the final state of a tracked transaction
+type TxOutcome struct {
+	Hash         string // hash that was mined (differs from the original if it was rebroadcast)
+	Status       string // types.TxConfirmed, TxReverted or TxDropped
+	BlockNumber  uint64
+	GasUsed      uint64
+	RevertReason string
+}
+
+// Tracker follows a sent transaction until it has enough confirmations,
+// rebroadcasting it with bumped fees if it sits unmined for too long.
+type Tracker struct {
+	client *Client
+	cfg    config.ChainConfig
+	errors abi.ABI
+}
+
+func NewTracker(client *Client, cfg config.ChainConfig) (*Tracker, error) {
+	parsed, err := AssetRegistryMetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse registry abi: %v", err)
+	}
+	return &Tracker{client: client, cfg: cfg, errors: *parsed}, nil
+}
+
+// Track blocks until tx (or a fee-bumped replacement) is final, or ctx ends
+func (t *Tracker) Track(ctx context.Context, tx *ethtypes.Transaction) TxOutcome {
+	eth := t.client.EthClient
+	sent := []*ethtypes.Transaction{tx}
+	lastBroadcast := time.Now()
+	deadline := time.Now().Add(t.cfg.DropAfter)
+
+	ticker := time.NewTicker(t.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return TxOutcome{Hash: sent[len(sent)-1].Hash().Hex(), Status: types.TxPending, RevertReason: ctx.Err().Error()}
+		case <-ticker.C:
+		}
+
+		// Read before the receipts, so a nonce consumed by one of our own
+		// broadcasts always shows up as its receipt below
+		nonce, nonceErr := eth.NonceAt(ctx, t.client.Address(), nil)
+
+		// Any of our broadcasts may be the one that got mined
+		for _, candidate := range sent {
+			receipt, err := eth.TransactionReceipt(ctx, candidate.Hash())
+			if err != nil {
+				continue
+			}
+			if outcome, final := t.confirm(ctx, candidate, receipt); final {
+				return outcome
+			}
+			// Mined but not deep enough yet; don't rebroadcast
+			lastBroadcast = time.Now()
+		}
+
+		// Nonce used by a transaction we don't know about (e.g. replaced externally)
+		if nonceErr == nil && nonce > tx.Nonce() && time.Since(lastBroadcast) > t.cfg.StuckAfter {
+			return TxOutcome{Hash: sent[len(sent)-1].Hash().Hex(), Status: types.TxDropped, RevertReason: "nonce consumed by another transaction"}
+		}
+
+		if time.Now().After(deadline) {
+			// Later nonces can't be mined until this one is filled
+			t.client.Queue.Resync()
+			return TxOutcome{Hash: sent[len(sent)-1].Hash().Hex(), Status: types.TxDropped, RevertReason: "not mined before drop deadline"}
+		}
+
+		if time.Since(lastBroadcast) > t.cfg.StuckAfter && len(sent) <= t.cfg.MaxBumps {
+			bumped, err := t.rebroadcast(ctx, sent[len(sent)-1])
+			if err != nil {
+				log.Printf("Rebroadcast of %s failed: %v", sent[len(sent)-1].Hash().Hex(), err)
+			} else {
+				log.Printf("Rebroadcast %s as %s with bumped fees", sent[len(sent)-1].Hash().Hex(), bumped.Hash().Hex())
+				sent = append(sent, bumped)
+			}
+			lastBroadcast = time.Now()
+		}
+	}
+}
+
+// confirm reports whether the receipt is final: reverted, or buried under enough
+// blocks and still canonical
+func (t *Tracker) confirm(ctx context.Context, tx *ethtypes.Transaction, receipt *ethtypes.Receipt) (TxOutcome, bool) {
+	outcome := TxOutcome{
+		Hash:        tx.Hash().Hex(),
+		BlockNumber: receipt.BlockNumber.Uint64(),
+		GasUsed:     receipt.GasUsed,
+	}
+
+	head, err := t.client.EthClient.BlockNumber(ctx)
+	if err != nil || head+1 < receipt.BlockNumber.Uint64()+t.cfg.Confirmations {
+		return outcome, false
+	}
+
+	// Guard against a reorg that orphaned the receipt's block
+	header, err := t.client.EthClient.HeaderByNumber(ctx, receipt.BlockNumber)
+	if err != nil || header.Hash() != receipt.BlockHash {
+		return outcome, false
+	}
+
+	if receipt.Status == ethtypes.ReceiptStatusSuccessful {
+		outcome.Status = types.TxConfirmed
+		return outcome, true
+	}
+
+	outcome.Status = types.TxReverted
+	outcome.RevertReason = t.revertReason(ctx, tx, receipt.BlockNumber)
+	return outcome, true
+}
+
+// revertReason replays the call against the parent block's state and decodes the
+// error data: Error(string), Panic(uint256) or a custom error from the registry ABI
+func (t *Tracker) revertReason(ctx context.Context, tx *ethtypes.Transaction, block *big.Int) string {
+	msg := ethereum.CallMsg{
+		From:  t.client.Address(),
+		To:    tx.To(),
+		Gas:   tx.Gas(),
+		Value: tx.Value(),
+		Data:  tx.Data(),
+	}
+	parent := new(big.Int).Sub(block, big.NewInt(1))
+
+	_, err := t.client.EthClient.CallContract(ctx, msg, parent)
+	if err == nil {
+		return "reverted (reason unavailable)"
+	}
+	return DecodeRevert(t.errors, err)
+}
+
+// DecodeRevert turns a call error into a readable reason, decoding revert data
+// against the given ABI's custom errors where possible
+func DecodeRevert(parsed abi.ABI, callErr error) string {
+	var dataErr rpc.DataError
+	if !errors.As(callErr, &dataErr) {
+		return callErr.Error()
+	}
+	hexData, ok := dataErr.ErrorData().(string)
+	if !ok {
+		return callErr.Error()
+	}
+	data, err := hexutil.Decode(hexData)
+	if err != nil || len(data) < 4 {
+		return callErr.Error()
+	}
+
+	if reason, err := abi.UnpackRevert(data); err == nil {
+		return reason
+	}
+
+	var selector [4]byte
+	copy(selector[:], data[:4])
+	abiErr, err := parsed.ErrorByID(selector)
+	if err != nil {
+		return fmt.Sprintf("unknown custom error 0x%x", data[:4])
+	}
+
+	values, err := abiErr.Inputs.Unpack(data[4:])
+	if err != nil {
+		return abiErr.Name
+	}
+	args := make([]string, len(values))
+	for i, v := range values {
+		if b, ok := v.([32]byte); ok {
+			v = common.Hash(b).Hex()
+		}
+		args[i] = fmt.Sprintf("%s=%v", abiErr.Inputs[i].Name, v)
+	}
+	return fmt.Sprintf("%s(%s)", abiErr.Name, strings.Join(args, ", "))
+}
+
+// rebroadcast re-signs tx with the same nonce and fees raised by GasBumpPercent.
+// It bypasses the TxQueue since it replaces a nonce the queue already handed out.
+func (t *Tracker) rebroadcast(ctx context.Context, tx *ethtypes.Transaction) (*ethtypes.Transaction, error) {
+	bump := func(v *big.Int) *big.Int {
+		bumped := new(big.Int).Mul(v, big.NewInt(int64(100+t.cfg.GasBumpPercent)))
+		return bumped.Div(bumped, big.NewInt(100))
+	}
+
+	var inner ethtypes.TxData
+	switch tx.Type() {
+	case ethtypes.DynamicFeeTxType:
+		inner = &ethtypes.DynamicFeeTx{
+			ChainID:   t.client.ChainID,
+			Nonce:     tx.Nonce(),
+			GasTipCap: bump(tx.GasTipCap()),
+			GasFeeCap: bump(tx.GasFeeCap()),
+			Gas:       tx.Gas(),
+			To:        tx.To(),
+			Value:     tx.Value(),
+			Data:      tx.Data(),
+		}
+	default:
+		inner = &ethtypes.LegacyTx{
+			Nonce:    tx.Nonce(),
+			GasPrice: bump(tx.GasPrice()),
+			Gas:      tx.Gas(),
+			To:       tx.To(),
+			Value:    tx.Value(),
+			Data:     tx.Data(),
+		}
+	}
+
+	signed, err := t.client.Signer.SignTx(ethtypes.NewTx(inner), t.client.ChainID)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.client.EthClient.SendTransaction(ctx, signed); err != nil {
+		return nil, err
+	}
+	return signed, nil
+}
+
+// Address returns the account the client sends transactions from
+func (c *Client) Address() common.Address {
+	return c.Signer.Address()
+}
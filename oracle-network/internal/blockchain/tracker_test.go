@@ -0,0 +1,274 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/yourorg/proptoken-oracle/internal/config"
+	"github.com/yourorg/proptoken-oracle/pkg/types"
+)
+
+func testChainConfig() config.ChainConfig {
+	return config.ChainConfig{
+		SendRetries:    2,
+		SendBackoff:    time.Millisecond,
+		Confirmations:  1,
+		PollInterval:   time.Millisecond,
+		StuckAfter:     time.Hour,
+		GasBumpPercent: 20,
+		DropAfter:      time.Hour,
+	}
+}
+
+// newTestTracker deploys a registry whose admin is the signer, without granting
+// it CONSENSUS_ROLE, and tracks through a client bound to it
+func newTestTracker(t *testing.T, cfg config.ChainConfig) (*simulated.Backend, *Client, *Tracker) {
+	t.Helper()
+	sim, backend, signer := newTestChain(t)
+	chainID, _ := backend.ChainID(context.Background())
+	auth, err := bind.NewKeyedTransactorWithChainID(crypto.ToECDSAUnsafe(common.FromHex(testKey)), chainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, _, _, err := DeployAssetRegistry(auth, backend, signer.Address())
+	if err != nil {
+		t.Fatalf("deploy: %v", err)
+	}
+	sim.Commit()
+
+	cfg.RegistryAddress = addr.Hex()
+	client, err := NewClientWithBackend(backend, cfg, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(client.Close)
+	tracker, err := NewTracker(client, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sim, client, tracker
+}
+
+// track runs Track in the background; the outcome arrives on the returned channel
+func track(t *testing.T, tracker *Tracker, tx *ethtypes.Transaction) <-chan TxOutcome {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	done := make(chan TxOutcome, 1)
+	go func() { done <- tracker.Track(ctx, tx) }()
+	return done
+}
+
+func waitOutcome(t *testing.T, done <-chan TxOutcome) TxOutcome {
+	t.Helper()
+	select {
+	case outcome := <-done:
+		return outcome
+	case <-time.After(5 * time.Second):
+		t.Fatal("transaction not final")
+		return TxOutcome{}
+	}
+}
+
+func assertPending(t *testing.T, done <-chan TxOutcome) {
+	t.Helper()
+	select {
+	case outcome := <-done:
+		t.Fatalf("final too early: %+v", outcome)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestTrackWaitsForConfirmations(t *testing.T) {
+	cfg := testChainConfig()
+	cfg.Confirmations = 3
+	sim, client, tracker := newTestTracker(t, cfg)
+
+	tx, err := client.Queue.Send(context.Background(), transfer(client.EthClient, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := track(t, tracker, tx)
+	assertPending(t, done)
+
+	sim.Commit() // mined: 1 confirmation
+	sim.Commit() // 2
+	assertPending(t, done)
+	sim.Commit() // 3
+
+	outcome := waitOutcome(t, done)
+	if outcome.Status != types.TxConfirmed || outcome.Hash != tx.Hash().Hex() {
+		t.Fatalf("outcome %+v, want %s confirmed", outcome, tx.Hash().Hex())
+	}
+	receipt, err := client.EthClient.TransactionReceipt(context.Background(), tx.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if outcome.BlockNumber != receipt.BlockNumber.Uint64() || outcome.GasUsed != receipt.GasUsed {
+		t.Errorf("outcome at block %d using %d gas, receipt at %d using %d", outcome.BlockNumber, outcome.GasUsed, receipt.BlockNumber, receipt.GasUsed)
+	}
+}
+
+func TestTrackRebroadcastsWithBumpedFees(t *testing.T) {
+	cfg := testChainConfig()
+	cfg.StuckAfter = 10 * time.Millisecond
+	cfg.MaxBumps = 1
+	sim, client, tracker := newTestTracker(t, cfg)
+
+	tx, err := client.Queue.Send(context.Background(), transfer(client.EthClient, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Signing is deterministic, so the replacement's hash is known up front
+	wantPrice := new(big.Int).Div(new(big.Int).Mul(testGasPrice, big.NewInt(120)), big.NewInt(100))
+	want, err := client.Signer.SignTx(ethtypes.NewTransaction(tx.Nonce(), *tx.To(), tx.Value(), tx.Gas(), wantPrice, nil), client.ChainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := track(t, tracker, tx)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, pending, err := sim.Client().TransactionByHash(context.Background(), want.Hash()); err == nil && pending {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("no fee-bumped replacement in the pool")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	sim.Commit()
+
+	outcome := waitOutcome(t, done)
+	if outcome.Status != types.TxConfirmed || outcome.Hash != want.Hash().Hex() {
+		t.Fatalf("outcome %+v, want replacement %s confirmed", outcome, want.Hash().Hex())
+	}
+	if _, err := client.EthClient.TransactionReceipt(context.Background(), tx.Hash()); !errors.Is(err, ethereum.NotFound) {
+		t.Errorf("original transaction mined as well (%v)", err)
+	}
+}
+
+func TestTrackDropsUnminedTransaction(t *testing.T) {
+	cfg := testChainConfig()
+	cfg.DropAfter = 20 * time.Millisecond
+	_, client, tracker := newTestTracker(t, cfg)
+
+	tx, err := client.Queue.Send(context.Background(), transfer(client.EthClient, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	outcome := waitOutcome(t, track(t, tracker, tx))
+	if outcome.Status != types.TxDropped || outcome.RevertReason != "not mined before drop deadline" {
+		t.Errorf("outcome %+v, want dropped", outcome)
+	}
+}
+
+func TestTrackDecodesRevert(t *testing.T) {
+	sim, client, tracker := newTestTracker(t, testChainConfig())
+
+	// The signer lacks CONSENSUS_ROLE; the gas limit skips estimation, which would refuse to send
+	tx, err := client.Queue.Send(context.Background(), func(opts *bind.TransactOpts) (*ethtypes.Transaction, error) {
+		opts.GasLimit = 200000
+		return client.Registry.UpdateAsset(opts, common.HexToHash("0x01"), common.Hash{}, common.Hash{})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := track(t, tracker, tx)
+	sim.Commit()
+
+	outcome := waitOutcome(t, done)
+	role := crypto.Keccak256Hash([]byte("CONSENSUS_ROLE"))
+	want := "AccessControlUnauthorizedAccount(account=" + client.Address().Hex() + ", neededRole=" + role.Hex() + ")"
+	if outcome.Status != types.TxReverted || outcome.RevertReason != want {
+		t.Errorf("outcome %+v, want reverted with %s", outcome, want)
+	}
+}
+
+func TestDecodeRevertFromChain(t *testing.T) {
+	sim, client, tracker := newTestTracker(t, testChainConfig())
+	call := func(from common.Address) error {
+		data, err := tracker.errors.Pack("updateAsset", common.HexToHash("0x01"), common.Hash{}, common.Hash{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = client.EthClient.CallContract(context.Background(), ethereum.CallMsg{From: from, To: &client.RegistryAddress, Data: data}, nil)
+		if err == nil {
+			t.Fatal("call did not revert")
+		}
+		return err
+	}
+
+	// Custom error, before the signer holds the role
+	other := common.HexToAddress("0x00000000000000000000000000000000000000AA")
+	role := crypto.Keccak256Hash([]byte("CONSENSUS_ROLE"))
+	want := "AccessControlUnauthorizedAccount(account=" + other.Hex() + ", neededRole=" + role.Hex() + ")"
+	if got := DecodeRevert(tracker.errors, call(other)); got != want {
+		t.Errorf("custom error decoded as %q, want %q", got, want)
+	}
+
+	// Error(string), once it does
+	if _, err := client.Queue.Send(context.Background(), func(opts *bind.TransactOpts) (*ethtypes.Transaction, error) {
+		return client.Registry.GrantRole(opts, role, client.Address())
+	}); err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+	if got := DecodeRevert(tracker.errors, call(client.Address())); got != "Asset not registered" {
+		t.Errorf("require decoded as %q", got)
+	}
+}
+
+// dataError is an RPC error carrying revert data, as a node returns for eth_call
+type dataError struct {
+	msg  string
+	data interface{}
+}
+
+func (e dataError) Error() string          { return e.msg }
+func (e dataError) ErrorData() interface{} { return e.data }
+
+func TestDecodeRevert(t *testing.T) {
+	parsed, err := AssetRegistryMetaData.GetAbi()
+	if err != nil {
+		t.Fatal(err)
+	}
+	uint256, _ := abi.NewType("uint256", "", nil)
+	panicData, err := abi.Arguments{{Type: uint256}}.Pack(big.NewInt(0x11))
+	if err != nil {
+		t.Fatal(err)
+	}
+	panicData = append(crypto.Keccak256([]byte("Panic(uint256)"))[:4], panicData...)
+	unauthorized := parsed.Errors["AccessControlUnauthorizedAccount"].ID
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"panic", dataError{"execution reverted", hexutil.Encode(panicData)}, "arithmetic underflow or overflow"},
+		{"unknown selector", dataError{"execution reverted", "0xdeadbeef"}, "unknown custom error 0xdeadbeef"},
+		{"truncated custom error", dataError{"execution reverted", hexutil.Encode(unauthorized[:4])}, "AccessControlUnauthorizedAccount"},
+		{"no revert data", dataError{"execution reverted", "0x"}, "execution reverted"},
+		{"not hex", dataError{"execution reverted", 42}, "execution reverted"},
+		{"plain error", errors.New("connection refused"), "connection refused"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := DecodeRevert(*parsed, tc.err); !strings.Contains(got, tc.want) {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,237 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/yourorg/proptoken-oracle/internal/config"
+	oraclecrypto "github.com/yourorg/proptoken-oracle/internal/crypto"
+)
+
+// TxBuilder signs and sends one transaction using the nonce set in opts,
+// typically a bound contract method such as AssetRegistry.RegisterAsset
+type TxBuilder func(opts *bind.TransactOpts) (*ethtypes.Transaction, error)
+
+// TxQueue is the single writer for the oracle account. Sends are serialised
+// through one goroutine that assigns nonces locally, so concurrent pushes
+// never race the node for the same nonce.
+type TxQueue struct {
+	backend Backend
+	auth    *bind.TransactOpts
+	retries int
+	backoff time.Duration
+
+	requests  chan txRequest
+	stopped   <-chan struct{} // closed when the run loop exits
+	needsSync atomic.Bool
+
+	// Owned by the run loop
+	nonce uint64
+}
+
+type txRequest struct {
+	ctx   context.Context
+	build TxBuilder
+	reply chan txReply
+}
+
+type txReply struct {
+	tx  *ethtypes.Transaction
+	err error
+}
+
+func NewTxQueue(backend Backend, signer oraclecrypto.Signer, chainID *big.Int, cfg config.ChainConfig) *TxQueue {
+	from := signer.Address()
+	auth := &bind.TransactOpts{
+		From: from,
+		Signer: func(addr common.Address, tx *ethtypes.Transaction) (*ethtypes.Transaction, error) {
+			if addr != from {
+				return nil, bind.ErrNotAuthorized
+			}
+			return signer.SignTx(tx, chainID)
+		},
+	}
+
+	q := &TxQueue{
+		backend:  backend,
+		auth:     auth,
+		retries:  cfg.SendRetries,
+		backoff:  cfg.SendBackoff,
+		requests: make(chan txRequest),
+	}
+	q.needsSync.Store(true)
+	return q
+}
+
+// ErrQueueStopped is returned by Send once the queue's context has ended
+var ErrQueueStopped = errors.New("tx queue stopped")
+
+// Start runs the writer loop until ctx is cancelled
+func (q *TxQueue) Start(ctx context.Context) {
+	q.stopped = ctx.Done()
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case req := <-q.requests:
+				tx, err := q.send(req)
+				req.reply <- txReply{tx, err}
+			}
+		}
+	}()
+}
+
+// Send queues build and waits for it to be sent. Sends are processed one at a time
+// in arrival order.
+func (q *TxQueue) Send(ctx context.Context, build TxBuilder) (*ethtypes.Transaction, error) {
+	req := txRequest{ctx: ctx, build: build, reply: make(chan txReply, 1)}
+	select {
+	case q.requests <- req:
+	case <-q.stopped:
+		return nil, ErrQueueStopped
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	select {
+	case res := <-req.reply:
+		return res.tx, res.err
+	case <-ctx.Done():
+		// The send may still complete; its nonce stays consumed either way
+		return nil, ctx.Err()
+	}
+}
+
+// Resync makes the next send reload the nonce from the node. Call it when a
+// transaction is known to have been dropped, leaving a gap.
+func (q *TxQueue) Resync() {
+	q.needsSync.Store(true)
+}
+
+// Address is the account the queue sends from
+func (q *TxQueue) Address() common.Address {
+	return q.auth.From
+}
+
+// send tries build with the next local nonce, retrying transient failures with backoff
+func (q *TxQueue) send(req txRequest) (*ethtypes.Transaction, error) {
+	backoff := q.backoff
+	for attempt := 0; ; attempt++ {
+		tx, err := q.attempt(req)
+		if err == nil {
+			return tx, nil
+		}
+
+		switch {
+		case isNonceTaken(err), isNonceStale(err):
+			// Another transaction (likely from before a restart) holds this nonce,
+			// or the local nonce drifted from the node; reload it rather than guess
+			q.needsSync.Store(true)
+		case !isRetryable(err):
+			return nil, err
+		}
+
+		if attempt >= q.retries {
+			return nil, fmt.Errorf("giving up after %d attempts: %v", attempt+1, err)
+		}
+		log.Printf("Tx send attempt %d failed, retrying in %s: %v", attempt+1, backoff, err)
+
+		select {
+		case <-time.After(backoff):
+			backoff *= 2
+		case <-req.ctx.Done():
+			return nil, req.ctx.Err()
+		}
+	}
+}
+
+func (q *TxQueue) attempt(req txRequest) (*ethtypes.Transaction, error) {
+	if q.needsSync.Load() {
+		if err := q.sync(req.ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	opts := *q.auth
+	opts.Context = req.ctx
+	opts.Nonce = new(big.Int).SetUint64(q.nonce)
+
+	// Keep the signed transaction so a resend the pool already holds counts as sent
+	var signed *ethtypes.Transaction
+	opts.Signer = func(addr common.Address, tx *ethtypes.Transaction) (*ethtypes.Transaction, error) {
+		stx, err := q.auth.Signer(addr, tx)
+		if err == nil {
+			signed = stx
+		}
+		return stx, err
+	}
+
+	tx, err := req.build(&opts)
+	if err != nil {
+		if signed == nil || !isAlreadyKnown(err) {
+			return nil, err
+		}
+		tx = signed
+	}
+	q.nonce++
+	return tx, nil
+}
+
+// sync loads the next nonce from the node's pending state. Transactions left in
+// the pool by a previous run are kept, and a gap left by a dropped one is refilled.
+func (q *TxQueue) sync(ctx context.Context) error {
+	pending, err := q.backend.PendingNonceAt(ctx, q.auth.From)
+	if err != nil {
+		return fmt.Errorf("failed to fetch pending nonce: %v", err)
+	}
+	mined, err := q.backend.NonceAt(ctx, q.auth.From, nil)
+	if err != nil {
+		return fmt.Errorf("failed to fetch nonce: %v", err)
+	}
+
+	if pending > mined {
+		log.Printf("Nonce sync: %d transactions from %s still pending", pending-mined, q.auth.From.Hex())
+	}
+	if pending < q.nonce {
+		log.Printf("Nonce sync: rewinding from %d to %d to fill a gap", q.nonce, pending)
+	}
+	q.nonce = pending
+	q.needsSync.Store(false)
+	return nil
+}
+
+// Node errors are only available as strings; these match geth's txpool messages.
+// isAlreadyKnown means the pool holds this exact transaction, so it was sent.
+func isAlreadyKnown(err error) bool {
+	return strings.Contains(strings.ToLower(err.Error()), "already known")
+}
+
+func isNonceTaken(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "nonce too low") || strings.Contains(msg, "replacement transaction underpriced")
+}
+
+func isNonceStale(err error) bool {
+	return strings.Contains(strings.ToLower(err.Error()), "nonce too high")
+}
+
+// isRetryable rejects failures that will recur however often the send is repeated
+func isRetryable(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, permanent := range []string{"execution reverted", "insufficient funds", "intrinsic gas too low", "invalid sender"} {
+		if strings.Contains(msg, permanent) {
+			return false
+		}
+	}
+	return true
+}
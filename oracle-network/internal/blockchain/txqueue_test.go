@@ -0,0 +1,219 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/yourorg/proptoken-oracle/internal/config"
+	oraclecrypto "github.com/yourorg/proptoken-oracle/internal/crypto"
+)
+
+const testKey = "b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291"
+
+var (
+	testGasPrice = big.NewInt(10 * params.GWei)
+	testSink     = common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+)
+
+func newTestChain(t *testing.T) (*simulated.Backend, Backend, *oraclecrypto.KeySigner) {
+	t.Helper()
+	signer, err := oraclecrypto.NewKeySigner(testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim := simulated.NewBackend(ethtypes.GenesisAlloc{
+		signer.Address(): {Balance: new(big.Int).Mul(big.NewInt(100), big.NewInt(params.Ether))},
+	})
+	t.Cleanup(func() { sim.Close() })
+	return sim, sim.Client(), signer
+}
+
+func newTestQueue(t *testing.T, backend Backend, signer oraclecrypto.Signer) *TxQueue {
+	t.Helper()
+	chainID, err := backend.ChainID(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := NewTxQueue(backend, signer, chainID, config.ChainConfig{SendRetries: 2, SendBackoff: time.Millisecond})
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	q.Start(ctx)
+	return q
+}
+
+// transfer builds a plain value transfer, signed and sent the way a bound
+// contract method is
+func transfer(backend Backend, value int64) TxBuilder {
+	return func(opts *bind.TransactOpts) (*ethtypes.Transaction, error) {
+		tx := ethtypes.NewTransaction(opts.Nonce.Uint64(), testSink, big.NewInt(value), params.TxGas, testGasPrice, nil)
+		signed, err := opts.Signer(opts.From, tx)
+		if err != nil {
+			return nil, err
+		}
+		if err := backend.SendTransaction(opts.Context, signed); err != nil {
+			return nil, err
+		}
+		return signed, nil
+	}
+}
+
+// sendDirect puts a transfer with the given nonce in the pool, bypassing the queue
+func sendDirect(t *testing.T, backend Backend, signer oraclecrypto.Signer, nonce uint64, value int64) *ethtypes.Transaction {
+	t.Helper()
+	chainID, _ := backend.ChainID(context.Background())
+	tx, err := signer.SignTx(ethtypes.NewTransaction(nonce, testSink, big.NewInt(value), params.TxGas, testGasPrice, nil), chainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := backend.SendTransaction(context.Background(), tx); err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+func TestTxQueueConcurrentSends(t *testing.T) {
+	sim, backend, signer := newTestChain(t)
+	q := newTestQueue(t, backend, signer)
+
+	const n = 10
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		nonces = make(map[uint64]bool)
+	)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			tx, err := q.Send(context.Background(), transfer(backend, int64(i+1)))
+			if err != nil {
+				t.Errorf("send %d: %v", i, err)
+				return
+			}
+			mu.Lock()
+			nonces[tx.Nonce()] = true
+			mu.Unlock()
+		}(i)
+	}
+	wg.Wait()
+
+	for i := uint64(0); i < n; i++ {
+		if !nonces[i] {
+			t.Errorf("nonce %d not used; got %v", i, nonces)
+		}
+	}
+	sim.Commit()
+	mined, err := backend.NonceAt(context.Background(), signer.Address(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mined != n {
+		t.Errorf("mined nonce = %d, want %d", mined, n)
+	}
+}
+
+func TestTxQueueResyncsAfterRestart(t *testing.T) {
+	_, backend, signer := newTestChain(t)
+
+	// A previous run left two transactions in the pool
+	first := newTestQueue(t, backend, signer)
+	for i := 0; i < 2; i++ {
+		if _, err := first.Send(context.Background(), transfer(backend, 1)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	restarted := newTestQueue(t, backend, signer)
+	tx, err := restarted.Send(context.Background(), transfer(backend, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Nonce() != 2 {
+		t.Errorf("nonce after restart = %d, want 2", tx.Nonce())
+	}
+}
+
+func TestTxQueueSkipsStuckNonce(t *testing.T) {
+	_, backend, signer := newTestChain(t)
+	q := newTestQueue(t, backend, signer)
+
+	if _, err := q.Send(context.Background(), transfer(backend, 1)); err != nil {
+		t.Fatal(err)
+	}
+	// Another writer takes the queue's next nonce with a different transaction,
+	// so the queue's send is rejected as an underpriced replacement
+	sendDirect(t, backend, signer, 1, 99)
+
+	tx, err := q.Send(context.Background(), transfer(backend, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Nonce() != 2 {
+		t.Errorf("nonce after stuck slot = %d, want 2", tx.Nonce())
+	}
+}
+
+func TestTxQueueAlreadyKnownIsSent(t *testing.T) {
+	_, backend, signer := newTestChain(t)
+	q := newTestQueue(t, backend, signer)
+
+	// The first attempt reaches the pool but its reply is lost, so the retry
+	// resends the same transaction and the node answers "already known"
+	var sent *ethtypes.Transaction
+	build := transfer(backend, 1)
+	lossy := func(opts *bind.TransactOpts) (*ethtypes.Transaction, error) {
+		tx, err := build(opts)
+		if err == nil && sent == nil {
+			sent = tx
+			return nil, errors.New("i/o timeout")
+		}
+		return tx, err
+	}
+
+	tx, err := q.Send(context.Background(), lossy)
+	if err != nil {
+		t.Fatalf("already-known send failed: %v", err)
+	}
+	if tx == nil || tx.Hash() != sent.Hash() {
+		t.Fatalf("got tx %v, want the pooled %s", tx, sent.Hash().Hex())
+	}
+
+	next, err := q.Send(context.Background(), transfer(backend, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next.Nonce() != 1 {
+		t.Errorf("next nonce = %d, want 1", next.Nonce())
+	}
+}
+
+func TestTxQueueRewindsOnResync(t *testing.T) {
+	sim, backend, signer := newTestChain(t)
+	q := newTestQueue(t, backend, signer)
+
+	if _, err := q.Send(context.Background(), transfer(backend, 1)); err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+
+	// Pretend a later send was dropped after the queue handed out its nonce
+	q.nonce = 5
+	q.Resync()
+
+	tx, err := q.Send(context.Background(), transfer(backend, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Nonce() != 1 {
+		t.Errorf("nonce after resync = %d, want 1", tx.Nonce())
+	}
+}
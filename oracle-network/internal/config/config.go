@@ -0,0 +1,654 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultPath is used when ORACLE_CONFIG is not set
+const DefaultPath = "configs/config.yaml"
+
+type Config struct {
+	Mode    string        `yaml:"mode"`
+	Server  ServerConfig  `yaml:"server"`
+	APIs    APIConfig     `yaml:"apis"`
+	Scoring ScoringConfig `yaml:"scoring"`
+	Signals SignalsConfig `yaml:"signals"`
+	Store   StoreConfig   `yaml:"store"`
+	Jobs    JobsConfig    `yaml:"jobs"`
+	Chain   ChainConfig   `yaml:"chain"`
+	Indexer IndexerConfig `yaml:"indexer"`
+	Signer  SignerConfig  `yaml:"signer"`
+
+	Attestation AttestationConfig `yaml:"attestation"`
+	Consensus   ConsensusConfig   `yaml:"consensus"`
+	Geo         GeoConfig         `yaml:"geo"`
+}
+
+// Run modes. Only dev tolerates the built-in key and an unverified oracle role.
+const (
+	ModeDev     = "dev"
+	ModeStaging = "staging"
+	ModeProd    = "prod"
+)
+
+type ServerConfig struct {
+	Port    int    `yaml:"port"`
+	Host    string `yaml:"host"`
+	Timeout int    `yaml:"timeout"` // seconds
+}
+
+type APIConfig struct {
+	Google   GoogleConfig   `yaml:"google"`
+	MCA      MCAConfig      `yaml:"mca"`
+	Activity ActivityConfig `yaml:"activity"`
+	Imagery  ImageryConfig  `yaml:"imagery"`
+	Vision   VisionConfig   `yaml:"vision"`
+}
+
+type GoogleConfig struct {
+	MapsAPIKey   string `yaml:"maps_api_key"`
+	VisionAPIKey string `yaml:"vision_api_key"`
+}
+
+type MCAConfig struct {
+	BaseURL string `yaml:"base_url"`
+	APIKey  string `yaml:"api_key"`
+}
+
+// ActivityConfig covers the utility, tax and occupancy data sources
+type ActivityConfig struct {
+	APIKey string `yaml:"api_key"`
+}
+
+// ImageryMockPrefix marks a TileURL served by synthetic tiles drawn in-process,
+// e.g. "mock://{z}/{x}/{y}". Only dev mode accepts it.
+const ImageryMockPrefix = "mock://"
+
+// ImageryConfig points the satellite client at an XYZ or WMTS (REST) tile
+// endpoint. TileURL takes {z}/{x}/{y} or {TileMatrix}/{TileCol}/{TileRow},
+// and {key} for APIKey.
+type ImageryConfig struct {
+	TileURL      string        `yaml:"tile_url"`
+	APIKey       string        `yaml:"api_key"`
+	Zoom         int           `yaml:"zoom"`
+	Radius       int           `yaml:"radius"` // tiles around the centre tile; 1 = 3x3 mosaic
+	CacheDir     string        `yaml:"cache_dir"`
+	CacheTTL     time.Duration `yaml:"cache_ttl"`
+	MinContrast  float64       `yaml:"min_contrast"` // luminance std dev (0-255) below which an image counts as blank
+	Placeholders []string      `yaml:"placeholders"` // sha256 hex of tiles the provider serves when it has no imagery
+}
+
+// Image analysis backends for building detection
+const (
+	VisionClassical = "classical" // edge density and built-up area, pure Go
+	VisionHTTP      = "http"      // POST the image to a model-serving endpoint
+	VisionONNX      = "onnx"      // run a local ONNX classifier on the CPU
+)
+
+// VisionConfig selects how the vision signal analyses fetched imagery
+type VisionConfig struct {
+	Backend   string        `yaml:"backend"`
+	URL       string        `yaml:"url"`       // http: inference endpoint
+	Model     string        `yaml:"model"`     // onnx: path to the .onnx file
+	Threshold float64       `yaml:"threshold"` // minimum confidence for a reported bounding box
+	Timeout   time.Duration `yaml:"timeout"`   // http: per request
+}
+
+// How verifiers treat degraded or failed signals
+const (
+	PolicyZero    = "zero"    // keep the weight, score the signal as 0
+	PolicyExclude = "exclude" // drop the signal and renormalise the remaining weights
+	PolicyFail    = "fail"    // fail the whole verification
+)
+
+// StoreConfig locates the embedded verification database
+type StoreConfig struct {
+	Path string `yaml:"path"`
+}
+
+// JobsConfig sizes the asynchronous verification worker pool
+type JobsConfig struct {
+	Workers        int           `yaml:"workers"`
+	QueueSize      int           `yaml:"queue_size"`
+	Timeout        time.Duration `yaml:"timeout"`         // per job
+	Retention      time.Duration `yaml:"retention"`       // how long finished jobs stay queryable
+	WebhookTimeout time.Duration `yaml:"webhook_timeout"` // per callback attempt
+}
+
+// ChainConfig locates the registry and controls how attestation
+// transactions are followed after they are sent
+type ChainConfig struct {
+	RPCURL          string        `yaml:"rpc_url"`
+	RegistryAddress string        `yaml:"registry_address"`
+	Confirmations   uint64        `yaml:"confirmations"`
+	PollInterval    time.Duration `yaml:"poll_interval"`
+	StuckAfter      time.Duration `yaml:"stuck_after"` // unmined this long => bump fees and rebroadcast
+	MaxBumps        int           `yaml:"max_bumps"`
+	GasBumpPercent  int           `yaml:"gas_bump_percent"` // nodes require >= 10 to accept a replacement
+	DropAfter       time.Duration `yaml:"drop_after"`       // give up and mark the tx dropped
+	SendRetries     int           `yaml:"send_retries"`
+	SendBackoff     time.Duration `yaml:"send_backoff"` // doubled after each failed send
+}
+
+// Signer backends
+const (
+	SignerKey      = "key"      // raw hex key from ORACLE_PRIVATE_KEY; dev only
+	SignerKeystore = "keystore" // encrypted keystore JSON + passphrase file
+	SignerExternal = "external" // Clef-compatible JSON-RPC signer
+)
+
+// SignerConfig selects where the oracle key lives
+type SignerConfig struct {
+	Type           string        `yaml:"type"`
+	Keystore       string        `yaml:"keystore"`
+	PassphraseFile string        `yaml:"passphrase_file"`
+	URL            string        `yaml:"url"`
+	Address        string        `yaml:"address"` // external: account to use (default: first)
+	Timeout        time.Duration `yaml:"timeout"` // external: per signing request
+}
+
+// AttestationConfig sets the EIP-712 domain attestations are signed under.
+// The verifying contract is chain.registry_address.
+type AttestationConfig struct {
+	Name    string        `yaml:"name"`
+	Version string        `yaml:"version"`
+	ChainID int64         `yaml:"chain_id"` // 0 = ask the chain node; must match it if both are set
+	TTL     time.Duration `yaml:"ttl"`      // how long a signed attestation stays valid
+}
+
+// ConsensusConfig runs verification on several oracle nodes. Each node verifies
+// independently; results are merged once Threshold of the N nodes (peers plus
+// this one) agree on the Merkle root.
+type ConsensusConfig struct {
+	Enabled      bool          `yaml:"enabled"`
+	Submitter    bool          `yaml:"submitter"`     // this node's account holds CONSENSUS_ROLE and pushes agreed results
+	SubmitterURL string        `yaml:"submitter_url"` // non-submitters redirect /verify here; empty rejects it
+	Threshold    int           `yaml:"threshold"`     // must be a majority of N
+	Peers        []PeerConfig  `yaml:"peers"`
+	Timeout      time.Duration `yaml:"timeout"`  // per peer request
+	MaxSkew      time.Duration `yaml:"max_skew"` // accepted clock difference on signed peer requests
+}
+
+// PeerConfig is another oracle node and the address its requests are signed with
+type PeerConfig struct {
+	URL     string `yaml:"url"`
+	Address string `yaml:"address"`
+}
+
+// GeoConfig points coordinate validation at GeoJSON files; an empty path uses
+// the bundled data for that layer
+type GeoConfig struct {
+	Boundaries string `yaml:"boundaries"` // states and cities
+	Landuse    string `yaml:"landuse"`    // water and non-buildable areas
+}
+
+// IndexerConfig controls the registry event indexer that serves /assets
+type IndexerConfig struct {
+	Enabled      bool          `yaml:"enabled"`
+	StartBlock   uint64        `yaml:"start_block"` // usually the registry's deployment block
+	BatchSize    uint64        `yaml:"batch_size"`  // blocks per log query while backfilling
+	PollInterval time.Duration `yaml:"poll_interval"`
+	ReorgDepth   uint64        `yaml:"reorg_depth"` // blocks kept rollback-able behind the head
+}
+
+type ScoringConfig struct {
+	Weights       map[string]float64 `yaml:"weights"`
+	Thresholds    Thresholds         `yaml:"thresholds"`
+	FailurePolicy string             `yaml:"failure_policy"`
+}
+
+// SignalsConfig lists the provider keys each verifier runs.
+// Every key must be registered in the provider registry and have a scoring weight.
+type SignalsConfig struct {
+	Existence []string `yaml:"existence"`
+	Ownership []string `yaml:"ownership"`
+	Activity  []string `yaml:"activity"`
+
+	// Per-provider deadline; Timeouts overrides Timeout for individual keys
+	Timeout  time.Duration            `yaml:"timeout"`
+	Timeouts map[string]time.Duration `yaml:"timeouts"`
+}
+
+type Thresholds struct {
+	MinExistence float64 `yaml:"min_existence"`
+	MinOwnership float64 `yaml:"min_ownership"`
+	MinActivity  float64 `yaml:"min_activity"`
+}
+
+// Load reads the YAML file at path, applies env overrides and validates the result.
+// If path is empty, ORACLE_CONFIG or DefaultPath is used.
+func Load(path string) (*Config, error) {
+	if path == "" {
+		path = os.Getenv("ORACLE_CONFIG")
+	}
+	if path == "" {
+		path = DefaultPath
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config %s: %v", path, err)
+	}
+
+	cfg := &Config{}
+	if err := yaml.Unmarshal(raw, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %v", path, err)
+	}
+
+	if err := cfg.applyEnv(); err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %v", path, err)
+	}
+	return cfg, nil
+}
+
+// applyEnv overrides file values with environment variables, if set
+func (c *Config) applyEnv() error {
+	envString("ORACLE_MODE", &c.Mode)
+	if v := os.Getenv("ORACLE_HOST"); v != "" {
+		c.Server.Host = v
+	}
+	if err := envInt("ORACLE_PORT", &c.Server.Port); err != nil {
+		return err
+	}
+	if err := envInt("ORACLE_TIMEOUT", &c.Server.Timeout); err != nil {
+		return err
+	}
+
+	envString("GOOGLE_MAPS_API_KEY", &c.APIs.Google.MapsAPIKey)
+	envString("GOOGLE_VISION_API_KEY", &c.APIs.Google.VisionAPIKey)
+	envString("MCA_BASE_URL", &c.APIs.MCA.BaseURL)
+	envString("MCA_API_KEY", &c.APIs.MCA.APIKey)
+	envString("ACTIVITY_API_KEY", &c.APIs.Activity.APIKey)
+	envString("IMAGERY_TILE_URL", &c.APIs.Imagery.TileURL)
+	envString("IMAGERY_API_KEY", &c.APIs.Imagery.APIKey)
+	envString("IMAGERY_CACHE_DIR", &c.APIs.Imagery.CacheDir)
+	envString("ORACLE_VISION_BACKEND", &c.APIs.Vision.Backend)
+	envString("VISION_URL", &c.APIs.Vision.URL)
+	envString("VISION_MODEL", &c.APIs.Vision.Model)
+
+	// ORACLE_WEIGHT_<NAME>, e.g. ORACLE_WEIGHT_VISION=0.4
+	if c.Scoring.Weights == nil {
+		c.Scoring.Weights = make(map[string]float64)
+	}
+	for _, kv := range os.Environ() {
+		key, value, _ := strings.Cut(kv, "=")
+		name, ok := strings.CutPrefix(key, "ORACLE_WEIGHT_")
+		if !ok {
+			continue
+		}
+		w, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %v", key, err)
+		}
+		c.Scoring.Weights[strings.ToLower(name)] = w
+	}
+
+	// ORACLE_SIGNALS_<CATEGORY>, comma separated, e.g. ORACLE_SIGNALS_EXISTENCE=satellite,vision
+	envList("ORACLE_SIGNALS_EXISTENCE", &c.Signals.Existence)
+	envList("ORACLE_SIGNALS_OWNERSHIP", &c.Signals.Ownership)
+	envList("ORACLE_SIGNALS_ACTIVITY", &c.Signals.Activity)
+
+	if v := os.Getenv("ORACLE_SIGNAL_TIMEOUT"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("invalid ORACLE_SIGNAL_TIMEOUT: %v", err)
+		}
+		c.Signals.Timeout = d
+	}
+
+	envString("BLOCKCHAIN_RPC_URL", &c.Chain.RPCURL)
+	envString("REGISTRY_CONTRACT_ADDRESS", &c.Chain.RegistryAddress)
+
+	if v := os.Getenv("ORACLE_INDEXER_ENABLED"); v != "" {
+		enabled, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("invalid ORACLE_INDEXER_ENABLED: %v", err)
+		}
+		c.Indexer.Enabled = enabled
+	}
+
+	if v := os.Getenv("ORACLE_CONSENSUS_ENABLED"); v != "" {
+		enabled, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("invalid ORACLE_CONSENSUS_ENABLED: %v", err)
+		}
+		c.Consensus.Enabled = enabled
+	}
+	envString("ORACLE_CONSENSUS_SUBMITTER_URL", &c.Consensus.SubmitterURL)
+
+	envString("ORACLE_SIGNER", &c.Signer.Type)
+	envString("ORACLE_KEYSTORE", &c.Signer.Keystore)
+	envString("ORACLE_KEYSTORE_PASSWORD_FILE", &c.Signer.PassphraseFile)
+	envString("ORACLE_SIGNER_URL", &c.Signer.URL)
+	envString("ORACLE_SIGNER_ADDRESS", &c.Signer.Address)
+
+	if v := os.Getenv("ORACLE_CHAIN_ID"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid ORACLE_CHAIN_ID: %v", err)
+		}
+		c.Attestation.ChainID = id
+	}
+
+	envString("ORACLE_GEO_BOUNDARIES", &c.Geo.Boundaries)
+	envString("ORACLE_GEO_LANDUSE", &c.Geo.Landuse)
+
+	envString("ORACLE_FAILURE_POLICY", &c.Scoring.FailurePolicy)
+	envString("ORACLE_STORE_PATH", &c.Store.Path)
+	if err := envInt("ORACLE_JOB_WORKERS", &c.Jobs.Workers); err != nil {
+		return err
+	}
+
+	if err := envFloat("ORACLE_MIN_EXISTENCE", &c.Scoring.Thresholds.MinExistence); err != nil {
+		return err
+	}
+	if err := envFloat("ORACLE_MIN_OWNERSHIP", &c.Scoring.Thresholds.MinOwnership); err != nil {
+		return err
+	}
+	return envFloat("ORACLE_MIN_ACTIVITY", &c.Scoring.Thresholds.MinActivity)
+}
+
+// Validate checks ranges and required fields
+func (c *Config) Validate() error {
+	switch c.Mode {
+	case ModeDev:
+	case ModeStaging, ModeProd:
+		if c.Chain.RPCURL == "" || c.Chain.RegistryAddress == "" {
+			return fmt.Errorf("mode %s requires chain.rpc_url and chain.registry_address to verify ORACLE_ROLE", c.Mode)
+		}
+		if strings.HasPrefix(c.APIs.Imagery.TileURL, ImageryMockPrefix) {
+			return fmt.Errorf("mode %s requires a real apis.imagery.tile_url, not synthetic %s tiles", c.Mode, ImageryMockPrefix)
+		}
+	default:
+		return fmt.Errorf("mode %q must be one of %s, %s, %s", c.Mode, ModeDev, ModeStaging, ModeProd)
+	}
+
+	if c.Server.Port <= 0 || c.Server.Port > 65535 {
+		return fmt.Errorf("server.port %d out of range", c.Server.Port)
+	}
+	if c.Server.Timeout <= 0 {
+		return fmt.Errorf("server.timeout must be positive")
+	}
+
+	for name, w := range c.Scoring.Weights {
+		if w < 0 || w > 1 {
+			return fmt.Errorf("scoring.weights.%s %v not in [0,1]", name, w)
+		}
+	}
+	if c.Store.Path == "" {
+		return fmt.Errorf("store.path is required")
+	}
+
+	if err := c.APIs.Imagery.validate(); err != nil {
+		return err
+	}
+	if err := c.APIs.Vision.validate(); err != nil {
+		return err
+	}
+
+	if c.Jobs.Workers <= 0 || c.Jobs.QueueSize <= 0 {
+		return fmt.Errorf("jobs.workers and jobs.queue_size must be positive")
+	}
+	if c.Jobs.Timeout <= 0 || c.Jobs.Retention <= 0 || c.Jobs.WebhookTimeout <= 0 {
+		return fmt.Errorf("jobs.timeout, jobs.retention and jobs.webhook_timeout must be positive")
+	}
+
+	if c.Chain.Confirmations == 0 {
+		return fmt.Errorf("chain.confirmations must be at least 1")
+	}
+	if c.Chain.PollInterval <= 0 || c.Chain.StuckAfter <= 0 || c.Chain.DropAfter <= 0 {
+		return fmt.Errorf("chain.poll_interval, chain.stuck_after and chain.drop_after must be positive")
+	}
+	if c.Chain.MaxBumps < 0 {
+		return fmt.Errorf("chain.max_bumps must not be negative")
+	}
+	if c.Chain.SendRetries < 0 || c.Chain.SendBackoff <= 0 {
+		return fmt.Errorf("chain.send_retries must not be negative and chain.send_backoff must be positive")
+	}
+	if c.Chain.GasBumpPercent < 10 {
+		return fmt.Errorf("chain.gas_bump_percent %d must be at least 10", c.Chain.GasBumpPercent)
+	}
+
+	if c.Indexer.Enabled {
+		if c.Indexer.BatchSize == 0 || c.Indexer.PollInterval <= 0 || c.Indexer.ReorgDepth == 0 {
+			return fmt.Errorf("indexer.batch_size, indexer.poll_interval and indexer.reorg_depth must be positive")
+		}
+	}
+
+	switch c.Signer.Type {
+	case SignerKey:
+	case SignerKeystore:
+		if c.Signer.Keystore == "" || c.Signer.PassphraseFile == "" {
+			return fmt.Errorf("signer.keystore and signer.passphrase_file are required for the keystore signer")
+		}
+	case SignerExternal:
+		if c.Signer.URL == "" || c.Signer.Timeout <= 0 {
+			return fmt.Errorf("signer.url and a positive signer.timeout are required for the external signer")
+		}
+	default:
+		return fmt.Errorf("signer.type %q must be one of %s, %s, %s",
+			c.Signer.Type, SignerKey, SignerKeystore, SignerExternal)
+	}
+
+	if c.Attestation.Name == "" || c.Attestation.Version == "" {
+		return fmt.Errorf("attestation.name and attestation.version are required")
+	}
+	if c.Attestation.ChainID < 0 || c.Attestation.TTL <= 0 {
+		return fmt.Errorf("attestation.chain_id must not be negative and attestation.ttl must be positive")
+	}
+
+	if c.Consensus.Enabled {
+		if err := c.Consensus.validate(); err != nil {
+			return err
+		}
+	}
+
+	switch c.Scoring.FailurePolicy {
+	case PolicyZero, PolicyExclude, PolicyFail:
+	default:
+		return fmt.Errorf("scoring.failure_policy %q must be one of %s, %s, %s",
+			c.Scoring.FailurePolicy, PolicyZero, PolicyExclude, PolicyFail)
+	}
+
+	if c.Signals.Timeout <= 0 {
+		return fmt.Errorf("signals.timeout must be positive")
+	}
+	for key, d := range c.Signals.Timeouts {
+		if d <= 0 {
+			return fmt.Errorf("signals.timeouts.%s must be positive", key)
+		}
+	}
+	if err := c.validateSignals("existence", c.Signals.Existence); err != nil {
+		return err
+	}
+	if err := c.validateSignals("ownership", c.Signals.Ownership); err != nil {
+		return err
+	}
+	if err := c.validateSignals("activity", c.Signals.Activity); err != nil {
+		return err
+	}
+
+	t := c.Scoring.Thresholds
+	if t.MinExistence < 0 || t.MinExistence > 1 {
+		return fmt.Errorf("scoring.thresholds.min_existence %v not in [0,1]", t.MinExistence)
+	}
+	if t.MinOwnership < 0 || t.MinOwnership > 1 {
+		return fmt.Errorf("scoring.thresholds.min_ownership %v not in [0,1]", t.MinOwnership)
+	}
+	if t.MinActivity < 0 || t.MinActivity > 1 {
+		return fmt.Errorf("scoring.thresholds.min_activity %v not in [0,1]", t.MinActivity)
+	}
+	return nil
+}
+
+// validateSignals checks a verifier has providers, each with a weight, and a non-zero total
+func (c *Config) validateSignals(category string, keys []string) error {
+	if len(keys) == 0 {
+		return fmt.Errorf("signals.%s must list at least one provider", category)
+	}
+	total := 0.0
+	for _, key := range keys {
+		w, ok := c.Scoring.Weights[key]
+		if !ok {
+			return fmt.Errorf("signals.%s: provider %q has no scoring.weights entry", category, key)
+		}
+		total += w
+	}
+	if total == 0 {
+		return fmt.Errorf("signals.%s: weights sum to zero", category)
+	}
+	return nil
+}
+
+// TimeoutFor returns the deadline for a provider key
+func (s SignalsConfig) TimeoutFor(key string) time.Duration {
+	if d, ok := s.Timeouts[key]; ok {
+		return d
+	}
+	return s.Timeout
+}
+
+// Addr returns the listen address for the HTTP server
+func (s ServerConfig) Addr() string {
+	return fmt.Sprintf("%s:%d", s.Host, s.Port)
+}
+
+// TimeoutDuration returns the request timeout
+func (s ServerConfig) TimeoutDuration() time.Duration {
+	return time.Duration(s.Timeout) * time.Second
+}
+
+func (c *ConsensusConfig) validate() error {
+	nodes := len(c.Peers) + 1
+	if c.Threshold < 1 || c.Threshold > nodes || 2*c.Threshold <= nodes {
+		return fmt.Errorf("consensus.threshold %d must be a majority of the %d nodes", c.Threshold, nodes)
+	}
+	if c.Timeout <= 0 || c.MaxSkew <= 0 {
+		return fmt.Errorf("consensus.timeout and consensus.max_skew must be positive")
+	}
+	if c.SubmitterURL != "" {
+		if c.Submitter {
+			return fmt.Errorf("consensus.submitter_url is only for nodes that are not the submitter")
+		}
+		if u, err := url.Parse(c.SubmitterURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("consensus.submitter_url %q must be an http(s) URL", c.SubmitterURL)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for i, p := range c.Peers {
+		if p.URL == "" || !common.IsHexAddress(p.Address) {
+			return fmt.Errorf("consensus.peers[%d] needs a url and a valid address", i)
+		}
+		addr := strings.ToLower(p.Address)
+		if seen[addr] {
+			return fmt.Errorf("consensus.peers[%d]: duplicate address %s", i, p.Address)
+		}
+		seen[addr] = true
+	}
+	return nil
+}
+
+func envString(key string, dst *string) {
+	if v := os.Getenv(key); v != "" {
+		*dst = v
+	}
+}
+
+func envList(key string, dst *[]string) {
+	v := os.Getenv(key)
+	if v == "" {
+		return
+	}
+	var list []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	*dst = list
+}
+
+func envInt(key string, dst *int) error {
+	v := os.Getenv(key)
+	if v == "" {
+		return nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %v", key, err)
+	}
+	*dst = n
+	return nil
+}
+
+func envFloat(key string, dst *float64) error {
+	v := os.Getenv(key)
+	if v == "" {
+		return nil
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %v", key, err)
+	}
+	*dst = f
+	return nil
+}
+
+func (c *ImageryConfig) validate() error {
+	hasXYZ := strings.Contains(c.TileURL, "{z}") && strings.Contains(c.TileURL, "{x}") && strings.Contains(c.TileURL, "{y}")
+	hasWMTS := strings.Contains(c.TileURL, "{TileMatrix}") && strings.Contains(c.TileURL, "{TileCol}") && strings.Contains(c.TileURL, "{TileRow}")
+	if !hasXYZ && !hasWMTS {
+		return fmt.Errorf("apis.imagery.tile_url must contain {z}, {x} and {y} or {TileMatrix}, {TileCol} and {TileRow}")
+	}
+	if c.Zoom < 0 || c.Zoom > 22 {
+		return fmt.Errorf("apis.imagery.zoom %d not in [0,22]", c.Zoom)
+	}
+	if c.Radius < 0 || c.Radius > 3 {
+		return fmt.Errorf("apis.imagery.radius %d not in [0,3]", c.Radius)
+	}
+	if c.CacheDir != "" && c.CacheTTL <= 0 {
+		return fmt.Errorf("apis.imagery.cache_ttl must be positive when cache_dir is set")
+	}
+	if c.MinContrast < 0 {
+		return fmt.Errorf("apis.imagery.min_contrast must not be negative")
+	}
+	for _, h := range c.Placeholders {
+		if len(h) != 64 {
+			return fmt.Errorf("apis.imagery.placeholders: %q is not a sha256 hex digest", h)
+		}
+	}
+	return nil
+}
+
+func (c *VisionConfig) validate() error {
+	switch c.Backend {
+	case VisionClassical:
+	case VisionHTTP:
+		if c.URL == "" || c.Timeout <= 0 {
+			return fmt.Errorf("apis.vision.url and a positive apis.vision.timeout are required for the http backend")
+		}
+	case VisionONNX:
+		if c.Model == "" {
+			return fmt.Errorf("apis.vision.model is required for the onnx backend")
+		}
+	default:
+		return fmt.Errorf("apis.vision.backend %q must be one of %s, %s, %s",
+			c.Backend, VisionClassical, VisionHTTP, VisionONNX)
+	}
+	if c.Threshold < 0 || c.Threshold > 1 {
+		return fmt.Errorf("apis.vision.threshold %v not in [0,1]", c.Threshold)
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var shippedConfig = filepath.Join("..", "..", "configs", "config.yaml")
+
+func loadShipped(t *testing.T) *Config {
+	t.Helper()
+	cfg, err := Load(shippedConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
+
+func TestLoadShippedConfig(t *testing.T) {
+	cfg := loadShipped(t)
+	if cfg.Mode != ModeDev || cfg.Server.Addr() != "0.0.0.0:8080" || cfg.Server.TimeoutDuration() != 300*time.Second {
+		t.Errorf("mode %s serving on %s with timeout %s", cfg.Mode, cfg.Server.Addr(), cfg.Server.TimeoutDuration())
+	}
+	if got := cfg.Signals.TimeoutFor("satellite"); got != 20*time.Second {
+		t.Errorf("satellite timeout %s, want its override", got)
+	}
+	if got := cfg.Signals.TimeoutFor("geo"); got != cfg.Signals.Timeout {
+		t.Errorf("geo timeout %s, want the default %s", got, cfg.Signals.Timeout)
+	}
+}
+
+func TestLoadFromEnvPath(t *testing.T) {
+	t.Setenv("ORACLE_CONFIG", shippedConfig)
+	if _, err := Load(""); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("ORACLE_CONFIG", filepath.Join(t.TempDir(), "missing.yaml"))
+	if _, err := Load(""); err == nil || !strings.Contains(err.Error(), "missing.yaml") {
+		t.Errorf("got %v, want a read error for the ORACLE_CONFIG path", err)
+	}
+}
+
+func TestEnvOverrides(t *testing.T) {
+	for key, value := range map[string]string{
+		"ORACLE_MODE":                   "staging",
+		"ORACLE_PORT":                   "9090",
+		"ORACLE_TIMEOUT":                "60",
+		"IMAGERY_TILE_URL":              "https://tiles.example/{z}/{x}/{y}.png",
+		"ORACLE_VISION_BACKEND":         "onnx",
+		"VISION_MODEL":                  "models/buildings.onnx",
+		"ORACLE_WEIGHT_VISION":          "0.4",
+		"ORACLE_WEIGHT_DRONE":           "0.1",
+		"ORACLE_SIGNALS_EXISTENCE":      " satellite, drone ,,vision",
+		"ORACLE_SIGNAL_TIMEOUT":         "3s",
+		"BLOCKCHAIN_RPC_URL":            "http://localhost:8545",
+		"REGISTRY_CONTRACT_ADDRESS":     "0x5FbDB2315678afecb367f032d93F642f64180aa3",
+		"ORACLE_INDEXER_ENABLED":        "false",
+		"ORACLE_CONSENSUS_ENABLED":      "true",
+		"ORACLE_SIGNER":                 "external",
+		"ORACLE_SIGNER_URL":             "http://localhost:8550",
+		"ORACLE_CHAIN_ID":               "84532",
+		"ORACLE_FAILURE_POLICY":         "exclude",
+		"ORACLE_STORE_PATH":             "/var/lib/oracle/oracle.db",
+		"ORACLE_JOB_WORKERS":            "8",
+		"ORACLE_MIN_EXISTENCE":          "0.7",
+		"ORACLE_MIN_OWNERSHIP":          "0.75",
+		"ORACLE_MIN_ACTIVITY":           "0.5",
+		"ORACLE_GEO_BOUNDARIES":         "data/boundaries.geojson",
+		"ORACLE_KEYSTORE_PASSWORD_FILE": "/run/secrets/pass",
+	} {
+		t.Setenv(key, value)
+	}
+	cfg := loadShipped(t)
+
+	checks := []struct {
+		name      string
+		got, want interface{}
+	}{
+		{"mode", cfg.Mode, ModeStaging},
+		{"port", cfg.Server.Port, 9090},
+		{"timeout", cfg.Server.Timeout, 60},
+		{"tile url", cfg.APIs.Imagery.TileURL, "https://tiles.example/{z}/{x}/{y}.png"},
+		{"vision backend", cfg.APIs.Vision.Backend, VisionONNX},
+		{"vision model", cfg.APIs.Vision.Model, "models/buildings.onnx"},
+		{"vision weight", cfg.Scoring.Weights["vision"], 0.4},
+		{"new weight", cfg.Scoring.Weights["drone"], 0.1},
+		{"untouched weight", cfg.Scoring.Weights["satellite"], 0.30},
+		{"existence signals", strings.Join(cfg.Signals.Existence, ","), "satellite,drone,vision"},
+		{"ownership signals", strings.Join(cfg.Signals.Ownership, ","), "registry,deed"},
+		{"signal timeout", cfg.Signals.Timeout, 3 * time.Second},
+		{"rpc url", cfg.Chain.RPCURL, "http://localhost:8545"},
+		{"indexer", cfg.Indexer.Enabled, false},
+		{"consensus", cfg.Consensus.Enabled, true},
+		{"signer", cfg.Signer.Type, SignerExternal},
+		{"signer url", cfg.Signer.URL, "http://localhost:8550"},
+		{"chain id", cfg.Attestation.ChainID, int64(84532)},
+		{"failure policy", cfg.Scoring.FailurePolicy, PolicyExclude},
+		{"store", cfg.Store.Path, "/var/lib/oracle/oracle.db"},
+		{"workers", cfg.Jobs.Workers, 8},
+		{"min existence", cfg.Scoring.Thresholds.MinExistence, 0.7},
+		{"min ownership", cfg.Scoring.Thresholds.MinOwnership, 0.75},
+		{"min activity", cfg.Scoring.Thresholds.MinActivity, 0.5},
+		{"geo", cfg.Geo.Boundaries, "data/boundaries.geojson"},
+		{"passphrase file", cfg.Signer.PassphraseFile, "/run/secrets/pass"},
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("%s = %v, want %v", c.name, c.got, c.want)
+		}
+	}
+}
+
+func TestEnvOverridesRejectBadValues(t *testing.T) {
+	tests := []struct {
+		key, value string
+		wantErr    string
+	}{
+		{"ORACLE_PORT", "http", "ORACLE_PORT"},
+		{"ORACLE_WEIGHT_VISION", "heavy", "ORACLE_WEIGHT_VISION"},
+		{"ORACLE_SIGNAL_TIMEOUT", "10", "ORACLE_SIGNAL_TIMEOUT"},
+		{"ORACLE_INDEXER_ENABLED", "maybe", "ORACLE_INDEXER_ENABLED"},
+		{"ORACLE_CONSENSUS_ENABLED", "maybe", "ORACLE_CONSENSUS_ENABLED"},
+		{"ORACLE_CHAIN_ID", "base", "ORACLE_CHAIN_ID"},
+		{"ORACLE_MIN_ACTIVITY", "high", "ORACLE_MIN_ACTIVITY"},
+		// Parsed, then refused by validation
+		{"ORACLE_PORT", "70000", "server.port 70000 out of range"},
+		{"ORACLE_WEIGHT_VISION", "1.5", "scoring.weights.vision"},
+		{"ORACLE_MODE", "prod", "mode prod requires chain.rpc_url"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.key+"="+tc.value, func(t *testing.T) {
+			t.Setenv(tc.key, tc.value)
+			_, err := Load(shippedConfig)
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("got %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateRejects(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(c *Config)
+		wantErr string
+	}{
+		{"mode", func(c *Config) { c.Mode = "test" }, `mode "test" must be one of`},
+		{"mock tiles outside dev", func(c *Config) {
+			c.Mode = ModeProd
+			c.Chain.RPCURL, c.Chain.RegistryAddress = "http://localhost:8545", "0x5FbDB2315678afecb367f032d93F642f64180aa3"
+		}, "requires a real apis.imagery.tile_url"},
+		{"server", func(c *Config) { c.Server.Timeout = 0 }, "server.timeout must be positive"},
+		{"imagery", func(c *Config) { c.APIs.Imagery.TileURL = "https://tiles.example/latest.png" }, "apis.imagery.tile_url must contain"},
+		{"imagery zoom", func(c *Config) { c.APIs.Imagery.Zoom = 23 }, "apis.imagery.zoom 23"},
+		{"imagery placeholder", func(c *Config) { c.APIs.Imagery.Placeholders = []string{"abc"} }, "not a sha256 hex digest"},
+		{"vision", func(c *Config) { c.APIs.Vision.Backend = VisionHTTP }, "apis.vision.url"},
+		{"vision threshold", func(c *Config) { c.APIs.Vision.Threshold = 2 }, "apis.vision.threshold 2"},
+		{"weights", func(c *Config) { c.Scoring.Weights["deed"] = -0.1 }, "scoring.weights.deed"},
+		{"thresholds", func(c *Config) { c.Scoring.Thresholds.MinOwnership = 1.1 }, "scoring.thresholds.min_ownership"},
+		{"failure policy", func(c *Config) { c.Scoring.FailurePolicy = "ignore" }, `scoring.failure_policy "ignore"`},
+		{"signals", func(c *Config) { c.Signals.Activity = nil }, "signals.activity must list at least one provider"},
+		{"signal weight", func(c *Config) { c.Signals.Ownership = append(c.Signals.Ownership, "notary") }, `provider "notary" has no scoring.weights entry`},
+		{"signal weights", func(c *Config) { c.Scoring.Weights["registry"], c.Scoring.Weights["deed"] = 0, 0 }, "signals.ownership: weights sum to zero"},
+		{"signal timeout", func(c *Config) { c.Signals.Timeouts["vision"] = 0 }, "signals.timeouts.vision"},
+		{"store", func(c *Config) { c.Store.Path = "" }, "store.path is required"},
+		{"jobs", func(c *Config) { c.Jobs.QueueSize = 0 }, "jobs.workers and jobs.queue_size"},
+		{"jobs timeouts", func(c *Config) { c.Jobs.WebhookTimeout = 0 }, "jobs.webhook_timeout"},
+		{"chain", func(c *Config) { c.Chain.Confirmations = 0 }, "chain.confirmations"},
+		{"chain gas bump", func(c *Config) { c.Chain.GasBumpPercent = 5 }, "chain.gas_bump_percent 5"},
+		{"chain retries", func(c *Config) { c.Chain.SendRetries = -1 }, "chain.send_retries"},
+		{"indexer", func(c *Config) { c.Indexer.ReorgDepth = 0 }, "indexer.batch_size, indexer.poll_interval and indexer.reorg_depth"},
+		{"signer", func(c *Config) { c.Signer.Type = "hsm" }, `signer.type "hsm"`},
+		{"keystore signer", func(c *Config) { c.Signer.Type = SignerKeystore }, "signer.keystore and signer.passphrase_file"},
+		{"attestation", func(c *Config) { c.Attestation.TTL = 0 }, "attestation.ttl must be positive"},
+		{"consensus threshold", func(c *Config) {
+			c.Consensus.Enabled = true
+			c.Consensus.Peers = []PeerConfig{{URL: "http://oracle-2:8080", Address: "0x5FbDB2315678afecb367f032d93F642f64180aa3"}}
+		}, "consensus.threshold 1 must be a majority of the 2 nodes"},
+		{"consensus peers", func(c *Config) {
+			c.Consensus.Enabled, c.Consensus.Threshold = true, 2
+			c.Consensus.Peers = []PeerConfig{{URL: "http://oracle-2:8080", Address: "oracle-2"}}
+		}, "consensus.peers[0] needs a url and a valid address"},
+		{"consensus submitter url", func(c *Config) {
+			c.Consensus.Enabled = true
+			c.Consensus.SubmitterURL = "http://oracle-1:8080"
+		}, "consensus.submitter_url is only for nodes that are not the submitter"},
+		{"consensus submitter url scheme", func(c *Config) {
+			c.Consensus.Enabled, c.Consensus.Submitter = true, false
+			c.Consensus.SubmitterURL = "oracle-1:8080"
+		}, "must be an http(s) URL"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := loadShipped(t)
+			tc.mutate(cfg)
+			err := cfg.Validate()
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("got %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadRejectsMalformedYAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("server: [port: 8080"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "failed to parse config") {
+		t.Errorf("got %v, want a parse error", err)
+	}
+}
@@ -0,0 +1,207 @@
+package consensus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yourorg/proptoken-oracle/internal/config"
+	"github.com/yourorg/proptoken-oracle/internal/handlers"
+	"github.com/yourorg/proptoken-oracle/pkg/types"
+)
+
+// Coordinator drives a consensus round for submissions received by this node.
+// It verifies locally while every peer does the same, keeps the results signed
+// by their node, and merges them once Threshold nodes agree on the Merkle root.
+// It implements jobs.Pipeline.
+type Coordinator struct {
+	local      *handlers.OracleAggregator
+	peers      []Peer
+	threshold  int
+	submitter  bool
+	thresholds config.Thresholds
+	client     *http.Client
+}
+
+func NewCoordinator(local *handlers.OracleAggregator, cfg config.ConsensusConfig, thresholds config.Thresholds) *Coordinator {
+	return &Coordinator{
+		local:      local,
+		peers:      peersFrom(cfg),
+		threshold:  cfg.Threshold,
+		submitter:  cfg.Submitter,
+		thresholds: thresholds,
+		client:     &http.Client{Timeout: cfg.Timeout},
+	}
+}
+
+// vote is one node's result, or why it has none
+type vote struct {
+	peer   Peer // zero URL for this node
+	result *types.OracleResult
+	err    error
+}
+
+// Attest runs the round and returns the merged result, signed by this node
+func (c *Coordinator) Attest(ctx context.Context, sub *types.SubmissionData) (*types.OracleResult, error) {
+	// Every node runs under the same inputs, so honest nodes reach the same root
+	inputs := c.local.NewInputs()
+	body, err := json.Marshal(attestRequest{Submission: *sub, Inputs: inputs})
+	if err != nil {
+		return nil, err
+	}
+
+	votes := make([]vote, len(c.peers)+1)
+	var wg sync.WaitGroup
+	wg.Add(len(votes))
+	go func() {
+		defer wg.Done()
+		result, err := c.local.AttestWith(ctx, sub, inputs)
+		votes[0] = vote{peer: Peer{Address: c.local.Signer.Address()}, result: result, err: err}
+	}()
+	for i, peer := range c.peers {
+		go func(i int, peer Peer) {
+			defer wg.Done()
+			result, err := c.request(ctx, peer, body)
+			if err == nil {
+				err = c.check(sub, peer, result)
+			}
+			votes[i+1] = vote{peer: peer, result: result, err: err}
+		}(i, peer)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("consensus aborted: %v", err)
+	}
+	return c.merge(sub, votes)
+}
+
+// check accepts a peer's result only if it is for this submission, its root
+// matches its signals, and the attestation is signed by that peer
+func (c *Coordinator) check(sub *types.SubmissionData, peer Peer, result *types.OracleResult) error {
+	if result.SubmissionID != sub.ID {
+		return fmt.Errorf("result is for submission %q", result.SubmissionID)
+	}
+	signer, err := c.local.RecoverSigner(result)
+	if err != nil {
+		return err
+	}
+	if signer != peer.Address {
+		// Also the symptom of a peer signing under a different domain
+		return fmt.Errorf("attestation signed by %s", signer.Hex())
+	}
+	if time.Now().After(result.Attestation.Expiry) {
+		return fmt.Errorf("attestation expired at %s", result.Attestation.Expiry.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// merge picks the Merkle root most nodes agree on and, if at least threshold
+// do, takes their shared result and signs it as the merged result
+func (c *Coordinator) merge(sub *types.SubmissionData, votes []vote) (*types.OracleResult, error) {
+	var (
+		roots   []string // in first-seen order, so this node's root wins ties
+		byRoot  = make(map[string][]vote)
+		summary = &types.ConsensusData{Threshold: c.threshold, Nodes: len(votes)}
+	)
+	for _, v := range votes {
+		if v.err != nil {
+			continue
+		}
+		root := strings.ToLower(v.result.Attestation.MerkleRoot)
+		if _, ok := byRoot[root]; !ok {
+			roots = append(roots, root)
+		}
+		byRoot[root] = append(byRoot[root], v)
+	}
+
+	var agreed string
+	for _, root := range roots {
+		if len(byRoot[root]) > len(byRoot[agreed]) {
+			agreed = root
+		}
+	}
+	summary.AgreedRoot = agreed
+
+	for _, v := range votes {
+		nv := types.NodeVote{Address: v.peer.Address.Hex(), URL: v.peer.URL}
+		switch {
+		case v.err != nil:
+			nv.Error = v.err.Error()
+		case strings.EqualFold(v.result.Attestation.MerkleRoot, agreed):
+			nv.MerkleRoot = v.result.Attestation.MerkleRoot
+			nv.Digest = v.result.Attestation.Digest
+			nv.Signature = v.result.Attestation.Signature
+			summary.Agreeing = append(summary.Agreeing, nv)
+			continue
+		default:
+			nv.MerkleRoot = v.result.Attestation.MerkleRoot
+			nv.Error = "merkle root differs from the agreed root"
+		}
+		summary.Disagreements = append(summary.Disagreements, nv)
+		log.Printf("Consensus: %s disagrees on %s: %s", nodeName(v.peer), sub.ID, nv.Error)
+	}
+
+	agreeing := byRoot[agreed]
+	if len(agreeing) < c.threshold {
+		return nil, fmt.Errorf("no consensus on %s: %d of %d nodes agree, %d required", sub.ID, len(agreeing), len(votes), c.threshold)
+	}
+
+	merged := c.mergeScores(agreeing)
+	merged.Consensus = summary
+	if err := c.local.Sign(merged); err != nil {
+		return nil, err
+	}
+	return merged, nil
+}
+
+// mergeScores combines results that share a Merkle root. Their signals are
+// identical (they hash to the same root), so the scores derived from them are
+// too; the first agreeing result, this node's when it agrees, is taken as is and
+// only its pass flags are re-applied against this node's thresholds.
+func (c *Coordinator) mergeScores(agreeing []vote) *types.OracleResult {
+	base := agreeing[0].result
+	merged := &types.OracleResult{
+		SubmissionID: base.SubmissionID,
+		Existence:    base.Existence,
+		Ownership:    base.Ownership,
+		Activity:     base.Activity,
+		Attestation: types.AttestationData{
+			MerkleRoot: base.Attestation.MerkleRoot,
+			Proofs:     base.Attestation.Proofs,
+		},
+		Inputs:    base.Inputs,
+		Timestamp: base.Inputs.ObservedAt,
+	}
+	merged.Existence.Passed = merged.Existence.Score >= c.thresholds.MinExistence
+	merged.Ownership.Passed = merged.Ownership.Score >= c.thresholds.MinOwnership
+	merged.Activity.Passed = merged.Activity.Score >= c.thresholds.MinActivity
+	return merged
+}
+
+// ErrNotSubmitter is returned by Anchor on nodes that do not hold
+// CONSENSUS_ROLE; submissions belong on the submitter node
+var ErrNotSubmitter = errors.New("this node is not the consensus submitter")
+
+// Anchor pushes the merged result if this node is the consensus submitter.
+// Other nodes store it and fail, so the result is never left unanchored silently.
+func (c *Coordinator) Anchor(ctx context.Context, sub *types.SubmissionData, result *types.OracleResult) (string, error) {
+	if !c.submitter {
+		c.local.Record(sub, result)
+		return "", ErrNotSubmitter
+	}
+	return c.local.Anchor(ctx, sub, result)
+}
+
+func nodeName(p Peer) string {
+	if p.URL == "" {
+		return "this node"
+	}
+	return fmt.Sprintf("%s (%s)", p.URL, p.Address.Hex())
+}
@@ -0,0 +1,240 @@
+package consensus
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	gethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/yourorg/proptoken-oracle/internal/config"
+	"github.com/yourorg/proptoken-oracle/internal/crypto"
+	"github.com/yourorg/proptoken-oracle/internal/handlers"
+	"github.com/yourorg/proptoken-oracle/pkg/types"
+)
+
+var testDomain = crypto.AttestationDomain{
+	Name:              "PropToken Oracle",
+	Version:           "1",
+	ChainID:           big.NewInt(84532),
+	VerifyingContract: common.HexToAddress("0x1"),
+}
+
+// recordedProvider returns the same signal on every node that shares its evidence
+type recordedProvider struct {
+	name     string
+	evidence string
+}
+
+func (p recordedProvider) Name() string { return p.name }
+
+func (p recordedProvider) Fetch(ctx context.Context, sub *types.SubmissionData, env handlers.Env) (types.SignalData, error) {
+	return types.SignalData{
+		Source:    "recorded",
+		Score:     0.9,
+		Evidence:  []byte(p.evidence),
+		Timestamp: env.Now(),
+	}, nil
+}
+
+// testNode is an oracle node with its own key. Nodes given the same evidence
+// reach the same Merkle root.
+type testNode struct {
+	agg    *handlers.OracleAggregator
+	server *httptest.Server
+}
+
+func newAggregator(t *testing.T, evidence string, domain crypto.AttestationDomain) *handlers.OracleAggregator {
+	t.Helper()
+	key, err := gethcrypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := crypto.NewKeySigner(hex.EncodeToString(gethcrypto.FromECDSA(key)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	providers := func(names ...string) []handlers.WeightedProvider {
+		var wps []handlers.WeightedProvider
+		for _, name := range names {
+			wps = append(wps, handlers.WeightedProvider{
+				Key:      name,
+				Provider: recordedProvider{name: name, evidence: evidence},
+				Weight:   1,
+				Timeout:  time.Second,
+			})
+		}
+		return wps
+	}
+	scoring := config.ScoringConfig{FailurePolicy: config.PolicyExclude}
+	return handlers.NewOracleAggregator(
+		handlers.NewExistenceVerifier(providers("satellite_imagery"), scoring),
+		handlers.NewOwnershipVerifier(providers("deed"), scoring),
+		handlers.NewActivityVerifier(providers("utility"), scoring),
+		signer, nil, nil, nil, domain, time.Hour,
+	)
+}
+
+// newPeer serves a node that accepts requests from coordinator
+func newPeer(t *testing.T, coordinator common.Address, evidence string, domain crypto.AttestationDomain) *testNode {
+	t.Helper()
+	agg := newAggregator(t, evidence, domain)
+	cfg := config.ConsensusConfig{
+		Peers:   []config.PeerConfig{{Address: coordinator.Hex()}},
+		MaxSkew: time.Minute,
+	}
+	server := httptest.NewServer(NewNode(agg, cfg))
+	t.Cleanup(server.Close)
+	return &testNode{agg: agg, server: server}
+}
+
+func newCoordinator(local *handlers.OracleAggregator, threshold int, peers ...*testNode) *Coordinator {
+	cfg := config.ConsensusConfig{Threshold: threshold, Timeout: 5 * time.Second}
+	for _, p := range peers {
+		cfg.Peers = append(cfg.Peers, config.PeerConfig{URL: p.server.URL, Address: p.agg.Signer.Address().Hex()})
+	}
+	return NewCoordinator(local, cfg, config.Thresholds{MinExistence: 0.5, MinOwnership: 0.5, MinActivity: 0.5})
+}
+
+func TestAttestAcrossNodes(t *testing.T) {
+	const evidence = `{"tile":"z18/1/2"}`
+	otherDomain := testDomain
+	otherDomain.ChainID = big.NewInt(1)
+
+	local := newAggregator(t, evidence, testDomain)
+	coordinator := local.Signer.Address()
+	honest1 := newPeer(t, coordinator, evidence, testDomain)
+	honest2 := newPeer(t, coordinator, evidence, testDomain)
+	differentRoot := newPeer(t, coordinator, `{"tile":"z18/9/9"}`, testDomain)
+	wrongKey := newPeer(t, coordinator, evidence, otherDomain)
+	sub := &types.SubmissionData{ID: "sub-1"}
+
+	t.Run("agreement", func(t *testing.T) {
+		c := newCoordinator(local, 3, honest1, honest2, differentRoot, wrongKey)
+		result, err := c.Attest(context.Background(), sub)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		summary := result.Consensus
+		if summary.Nodes != 5 || summary.Threshold != 3 {
+			t.Errorf("summary covers %d nodes at threshold %d, want 5 at 3", summary.Nodes, summary.Threshold)
+		}
+		if summary.AgreedRoot != strings.ToLower(result.Attestation.MerkleRoot) {
+			t.Errorf("agreed root %s, result root %s", summary.AgreedRoot, result.Attestation.MerkleRoot)
+		}
+		agreeing := make(map[string]bool)
+		for _, v := range summary.Agreeing {
+			agreeing[v.Address] = true
+		}
+		for _, n := range []*handlers.OracleAggregator{local, honest1.agg, honest2.agg} {
+			if !agreeing[n.Signer.Address().Hex()] {
+				t.Errorf("%s missing from agreeing nodes", n.Signer.Address().Hex())
+			}
+		}
+
+		disagreements := make(map[string]types.NodeVote)
+		for _, v := range summary.Disagreements {
+			disagreements[v.Address] = v
+		}
+		if len(disagreements) != 2 {
+			t.Fatalf("%d disagreements, want 2: %+v", len(disagreements), summary.Disagreements)
+		}
+		v := disagreements[differentRoot.agg.Signer.Address().Hex()]
+		if v.MerkleRoot == "" || strings.EqualFold(v.MerkleRoot, result.Attestation.MerkleRoot) || !strings.Contains(v.Error, "differs") {
+			t.Errorf("different-root peer recorded as %+v", v)
+		}
+		v = disagreements[wrongKey.agg.Signer.Address().Hex()]
+		if !strings.Contains(v.Error, "attestation signed by") {
+			t.Errorf("wrong-key peer recorded as %+v", v)
+		}
+
+		// The merged result is signed by this node
+		signer, err := local.RecoverSigner(result)
+		if err != nil || signer != coordinator {
+			t.Errorf("merged result signed by %s (%v), want %s", signer.Hex(), err, coordinator.Hex())
+		}
+		if !result.Existence.Passed || !result.Ownership.Passed || !result.Activity.Passed {
+			t.Errorf("merged scores did not pass: %+v", result)
+		}
+
+		// Only the submitter may anchor
+		if _, err := c.Anchor(context.Background(), sub, result); !errors.Is(err, ErrNotSubmitter) {
+			t.Errorf("non-submitter anchor returned %v, want ErrNotSubmitter", err)
+		}
+	})
+
+	t.Run("below threshold", func(t *testing.T) {
+		c := newCoordinator(local, 3, honest1, differentRoot, wrongKey)
+		_, err := c.Attest(context.Background(), sub)
+		if err == nil || !strings.Contains(err.Error(), "2 of 4 nodes agree, 3 required") {
+			t.Fatalf("got error %v, want no consensus", err)
+		}
+	})
+
+	t.Run("unreachable peer", func(t *testing.T) {
+		gone := newPeer(t, coordinator, evidence, testDomain)
+		gone.server.Close()
+		c := newCoordinator(local, 2, honest1, gone)
+		result, err := c.Attest(context.Background(), sub)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result.Consensus.Agreeing) != 2 || len(result.Consensus.Disagreements) != 1 {
+			t.Errorf("summary %+v, want 2 agreeing and the unreachable peer", result.Consensus)
+		}
+	})
+}
+
+func TestCheck(t *testing.T) {
+	const evidence = `{"tile":"z18/1/2"}`
+	local := newAggregator(t, evidence, testDomain)
+	peer := newAggregator(t, evidence, testDomain)
+	other := newAggregator(t, evidence, testDomain)
+	c := newCoordinator(local, 1)
+	sub := &types.SubmissionData{ID: "sub-1"}
+
+	attest := func(a *handlers.OracleAggregator, observed time.Time) *types.OracleResult {
+		t.Helper()
+		inputs := types.RunInputs{ObservedAt: observed}
+		result, err := a.AttestWith(context.Background(), sub, inputs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+	now := time.Now()
+	tampered := attest(peer, now)
+	tampered.Existence.Signals["satellite_imagery"] = types.SignalData{Source: "forged", Score: 1}
+
+	tests := []struct {
+		name    string
+		result  *types.OracleResult
+		wantErr string
+	}{
+		{"valid", attest(peer, now), ""},
+		{"other submission", func() *types.OracleResult { r := attest(peer, now); r.SubmissionID = "sub-2"; return r }(), `submission "sub-2"`},
+		{"wrong key", attest(other, now), "attestation signed by " + other.Signer.Address().Hex()},
+		{"root not matching signals", tampered, "does not match signals"},
+		{"expired", attest(peer, now.Add(-2*time.Hour)), "expired"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := c.check(sub, Peer{Address: peer.Signer.Address()}, tc.result)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("got error %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package consensus
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/yourorg/proptoken-oracle/internal/config"
+	"github.com/yourorg/proptoken-oracle/internal/crypto"
+	"github.com/yourorg/proptoken-oracle/internal/handlers"
+	"github.com/yourorg/proptoken-oracle/pkg/types"
+)
+
+// AttestPath is where nodes accept peer verification requests
+const AttestPath = "/consensus/attest"
+
+// Peer requests and responses are signed with the node's oracle key (EIP-191).
+// A request signs "<timestamp>\n<body>"; the response signs
+// "<request signature>\n<body>", binding it to the request it answers.
+const (
+	headerAddress   = "X-Oracle-Address"
+	headerSignature = "X-Oracle-Signature"
+	headerTimestamp = "X-Oracle-Timestamp"
+
+	maxBody = 4 << 20
+)
+
+// attestRequest asks a peer to verify a submission under the coordinator's run
+// inputs, so every node observes at the same time
+type attestRequest struct {
+	Submission types.SubmissionData `json:"submission"`
+	Inputs     types.RunInputs      `json:"inputs"`
+}
+
+// Peer is another oracle node, identified by the address it signs with
+type Peer struct {
+	URL     string
+	Address common.Address
+}
+
+func peersFrom(cfg config.ConsensusConfig) []Peer {
+	peers := make([]Peer, len(cfg.Peers))
+	for i, p := range cfg.Peers {
+		peers[i] = Peer{URL: p.URL, Address: common.HexToAddress(p.Address)}
+	}
+	return peers
+}
+
+// Node serves this node's side of the peer protocol: an authenticated peer posts
+// a submission and gets back this node's independently verified and signed result.
+type Node struct {
+	local   *handlers.OracleAggregator
+	peers   map[common.Address]bool
+	maxSkew time.Duration
+}
+
+func NewNode(local *handlers.OracleAggregator, cfg config.ConsensusConfig) *Node {
+	allowed := make(map[common.Address]bool)
+	for _, p := range peersFrom(cfg) {
+		allowed[p.Address] = true
+	}
+	return &Node{local: local, peers: allowed, maxSkew: cfg.MaxSkew}
+}
+
+// ServeHTTP handles POST AttestPath
+func (n *Node) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBody))
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	from, err := n.authenticate(r.Header, body, time.Now())
+	if err != nil {
+		http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	var req attestRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if skew := time.Since(req.Inputs.ObservedAt); skew > n.maxSkew || skew < -n.maxSkew {
+		http.Error(w, "Observation time too far from this node's clock", http.StatusBadRequest)
+		return
+	}
+
+	log.Printf("Consensus: verifying %s for peer %s", req.Submission.ID, from.Hex())
+	result, err := n.local.AttestWith(r.Context(), &req.Submission, req.Inputs)
+	if err != nil {
+		http.Error(w, "Verification failed: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	resp, err := json.Marshal(result)
+	if err != nil {
+		http.Error(w, "Failed to encode result", http.StatusInternalServerError)
+		return
+	}
+	signature, err := crypto.SignPayload(n.local.Signer, responseMessage(r.Header.Get(headerSignature), resp))
+	if err != nil {
+		http.Error(w, "Failed to sign result: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set(headerAddress, n.local.Signer.Address().Hex())
+	w.Header().Set(headerSignature, signature)
+	w.Write(resp)
+}
+
+// authenticate checks the request was signed recently by a configured peer
+func (n *Node) authenticate(h http.Header, body []byte, now time.Time) (common.Address, error) {
+	ts, err := strconv.ParseInt(h.Get(headerTimestamp), 10, 64)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("missing or invalid %s", headerTimestamp)
+	}
+	if skew := now.Sub(time.Unix(ts, 0)); skew > n.maxSkew || skew < -n.maxSkew {
+		return common.Address{}, fmt.Errorf("request timestamp off by %s", skew.Round(time.Second))
+	}
+
+	from, err := crypto.RecoverText(requestMessage(ts, body), h.Get(headerSignature))
+	if err != nil {
+		return common.Address{}, err
+	}
+	if !n.peers[from] {
+		return common.Address{}, fmt.Errorf("%s is not a configured peer", from.Hex())
+	}
+	return from, nil
+}
+
+// request sends a submission to peer and returns its result once the response
+// signature is confirmed to be the peer's. The attestation inside is checked by the caller.
+func (c *Coordinator) request(ctx context.Context, peer Peer, body []byte) (*types.OracleResult, error) {
+	ts := time.Now().Unix()
+	signature, err := crypto.SignPayload(c.local.Signer, requestMessage(ts, body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign request: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, peer.URL+AttestPath, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(headerAddress, c.local.Signer.Address().Hex())
+	req.Header.Set(headerTimestamp, strconv.FormatInt(ts, 10))
+	req.Header.Set(headerSignature, signature)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("peer returned %s: %s", resp.Status, bytes.TrimSpace(respBody))
+	}
+
+	from, err := crypto.RecoverText(responseMessage(signature, respBody), resp.Header.Get(headerSignature))
+	if err != nil {
+		return nil, fmt.Errorf("invalid response signature: %v", err)
+	}
+	if from != peer.Address {
+		return nil, fmt.Errorf("response signed by %s", from.Hex())
+	}
+
+	var result types.OracleResult
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("invalid result: %v", err)
+	}
+	return &result, nil
+}
+
+func requestMessage(ts int64, body []byte) []byte {
+	return append([]byte(strconv.FormatInt(ts, 10)+"\n"), body...)
+}
+
+func responseMessage(requestSignature string, body []byte) []byte {
+	return append([]byte(requestSignature+"\n"), body...)
+}
@@ -0,0 +1,155 @@
+package crypto
+
+import (
+    "fmt"
+    "math/big"
+    "time"
+    "github.com/ethereum/go-ethereum/common"
+    "github.com/ethereum/go-ethereum/common/hexutil"
+    "github.com/ethereum/go-ethereum/common/math"
+    "github.com/ethereum/go-ethereum/crypto"
+    "github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// AttestationScheme names the signature scheme recorded alongside attestations
+const AttestationScheme = "eip712"
+
+// AttestationDomain is the EIP-712 domain attestations are signed under. Binding
+// the chain and registry stops a signature being replayed on another deployment.
+type AttestationDomain struct {
+    Name              string
+    Version           string
+    ChainID           *big.Int
+    VerifyingContract common.Address
+}
+
+// AttestationClaim is the typed struct the oracle signs. Scores are 18-decimal
+// fixed point, as pushed to the registry; times are unix seconds.
+type AttestationClaim struct {
+    Fingerprint    common.Hash
+    MerkleRoot     common.Hash
+    ExistenceScore *big.Int
+    OwnershipScore *big.Int
+    ActivityScore  *big.Int
+    Eligible       bool
+    Timestamp      uint64
+    Expiry         uint64
+}
+
+var attestationTypes = apitypes.Types{
+    "EIP712Domain": {
+        {Name: "name", Type: "string"},
+        {Name: "version", Type: "string"},
+        {Name: "chainId", Type: "uint256"},
+        {Name: "verifyingContract", Type: "address"},
+    },
+    "Attestation": {
+        {Name: "fingerprint", Type: "bytes32"},
+        {Name: "merkleRoot", Type: "bytes32"},
+        {Name: "existenceScore", Type: "uint256"},
+        {Name: "ownershipScore", Type: "uint256"},
+        {Name: "activityScore", Type: "uint256"},
+        {Name: "eligible", Type: "bool"},
+        {Name: "timestamp", Type: "uint256"},
+        {Name: "expiry", Type: "uint256"},
+    },
+}
+
+// AttestationTypedData builds the EIP-712 payload for claim under domain
+func AttestationTypedData(domain AttestationDomain, claim AttestationClaim) apitypes.TypedData {
+    chainID := domain.ChainID
+    if chainID == nil {
+        chainID = new(big.Int)
+    }
+    return apitypes.TypedData{
+        Types:       attestationTypes,
+        PrimaryType: "Attestation",
+        Domain: apitypes.TypedDataDomain{
+            Name:              domain.Name,
+            Version:           domain.Version,
+            ChainId:           (*math.HexOrDecimal256)(chainID),
+            VerifyingContract: domain.VerifyingContract.Hex(),
+        },
+        Message: apitypes.TypedDataMessage{
+            "fingerprint":    claim.Fingerprint.Hex(),
+            "merkleRoot":     claim.MerkleRoot.Hex(),
+            "existenceScore": uint256(claim.ExistenceScore),
+            "ownershipScore": uint256(claim.OwnershipScore),
+            "activityScore":  uint256(claim.ActivityScore),
+            "eligible":       claim.Eligible,
+            "timestamp":      uint256(new(big.Int).SetUint64(claim.Timestamp)),
+            "expiry":         uint256(new(big.Int).SetUint64(claim.Expiry)),
+        },
+    }
+}
+
+// AttestationDigest is the hash that is actually signed ("\x19\x01" || domainSeparator || structHash)
+func AttestationDigest(domain AttestationDomain, claim AttestationClaim) (common.Hash, error) {
+    hash, _, err := apitypes.TypedDataAndHash(AttestationTypedData(domain, claim))
+    if err != nil {
+        return common.Hash{}, fmt.Errorf("failed to hash attestation: %v", err)
+    }
+    return common.BytesToHash(hash), nil
+}
+
+// SignAttestation signs claim as EIP-712 typed data and returns the 0x signature (V 27/28)
+func SignAttestation(s Signer, domain AttestationDomain, claim AttestationClaim) (string, error) {
+    signature, err := s.SignTypedData(AttestationTypedData(domain, claim))
+    if err != nil {
+        return "", err
+    }
+
+    return hexutil.Encode(signature), nil
+}
+
+// RecoverAttestationSigner returns the address that signed claim under domain
+func RecoverAttestationSigner(domain AttestationDomain, claim AttestationClaim, signature string) (common.Address, error) {
+    digest, err := AttestationDigest(domain, claim)
+    if err != nil {
+        return common.Address{}, err
+    }
+    return recoverDigest(digest.Bytes(), signature)
+}
+
+// VerifyAttestation checks that signature over claim was made by expected and
+// that the claim had not expired at now
+func VerifyAttestation(domain AttestationDomain, claim AttestationClaim, signature string, expected common.Address, now time.Time) error {
+    signer, err := RecoverAttestationSigner(domain, claim, signature)
+    if err != nil {
+        return err
+    }
+    if signer != expected {
+        return fmt.Errorf("attestation signed by %s, expected %s", signer.Hex(), expected.Hex())
+    }
+    if claim.Expiry != 0 && uint64(now.Unix()) > claim.Expiry {
+        return fmt.Errorf("attestation expired at %s", time.Unix(int64(claim.Expiry), 0).UTC().Format(time.RFC3339))
+    }
+    return nil
+}
+
+func uint256(v *big.Int) *math.HexOrDecimal256 {
+    if v == nil {
+        v = new(big.Int)
+    }
+    return (*math.HexOrDecimal256)(v)
+}
+
+// recoverDigest returns the signer of a 65-byte signature over a 32-byte digest
+func recoverDigest(digest []byte, signature string) (common.Address, error) {
+    sig, err := hexutil.Decode(signature)
+    if err != nil {
+        return common.Address{}, fmt.Errorf("invalid signature encoding: %v", err)
+    }
+    if len(sig) != crypto.SignatureLength {
+        return common.Address{}, fmt.Errorf("invalid signature length %d", len(sig))
+    }
+    if sig[crypto.RecoveryIDOffset] >= 27 {
+        sig[crypto.RecoveryIDOffset] -= 27
+    }
+
+    pub, err := crypto.SigToPub(digest, sig)
+    if err != nil {
+        return common.Address{}, fmt.Errorf("failed to recover signer: %v", err)
+    }
+    return crypto.PubkeyToAddress(*pub), nil
+}
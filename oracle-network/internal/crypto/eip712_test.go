@@ -0,0 +1,165 @@
+package crypto
+
+import (
+    "math/big"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/ethereum/go-ethereum/accounts/abi"
+    "github.com/ethereum/go-ethereum/common"
+    "github.com/ethereum/go-ethereum/crypto"
+)
+
+// The domain the node signs under by default (configs/config.yaml), on Base Sepolia
+var testDomain = AttestationDomain{
+    Name:              "PropTokenOracle",
+    Version:           "1",
+    ChainID:           big.NewInt(84532),
+    VerifyingContract: common.HexToAddress("0x5FbDB2315678afecb367f032d93F642f64180aa3"),
+}
+
+var testClaim = AttestationClaim{
+    Fingerprint:    common.HexToHash("0x1c8aff950685c2ed4bc3174f3472287b56d9517b9c948127319a09a7a36deac8"),
+    MerkleRoot:     common.HexToHash("0x0c5a3b1a9d2e49a27c3ee3f2b5d9d3a6b3c8c0f1e2d3c4b5a69788796a5b4c3d"),
+    ExistenceScore: big.NewInt(9e17),
+    OwnershipScore: big.NewInt(8e17),
+    ActivityScore:  big.NewInt(75e16),
+    Eligible:       true,
+    Timestamp:      1700000000,
+    Expiry:         1702592000,
+}
+
+// solidityDigest hashes claim the way a contract does with OpenZeppelin's EIP712:
+// _hashTypedDataV4(keccak256(abi.encode(ATTESTATION_TYPEHASH, ...)))
+func solidityDigest(t *testing.T, domain AttestationDomain, claim AttestationClaim) common.Hash {
+    t.Helper()
+    encode := func(types []string, values ...interface{}) []byte {
+        var args abi.Arguments
+        for _, name := range types {
+            typ, err := abi.NewType(name, "", nil)
+            if err != nil {
+                t.Fatal(err)
+            }
+            args = append(args, abi.Argument{Type: typ})
+        }
+        packed, err := args.Pack(values...)
+        if err != nil {
+            t.Fatal(err)
+        }
+        return packed
+    }
+
+    domainTypehash := crypto.Keccak256Hash([]byte("EIP712Domain(string name,string version,uint256 chainId,address verifyingContract)"))
+    separator := crypto.Keccak256Hash(encode(
+        []string{"bytes32", "bytes32", "bytes32", "uint256", "address"},
+        domainTypehash,
+        crypto.Keccak256Hash([]byte(domain.Name)),
+        crypto.Keccak256Hash([]byte(domain.Version)),
+        domain.ChainID,
+        domain.VerifyingContract,
+    ))
+
+    typehash := crypto.Keccak256Hash([]byte("Attestation(bytes32 fingerprint,bytes32 merkleRoot,uint256 existenceScore,uint256 ownershipScore,uint256 activityScore,bool eligible,uint256 timestamp,uint256 expiry)"))
+    structHash := crypto.Keccak256Hash(encode(
+        []string{"bytes32", "bytes32", "bytes32", "uint256", "uint256", "uint256", "bool", "uint256", "uint256"},
+        typehash,
+        claim.Fingerprint,
+        claim.MerkleRoot,
+        claim.ExistenceScore,
+        claim.OwnershipScore,
+        claim.ActivityScore,
+        claim.Eligible,
+        new(big.Int).SetUint64(claim.Timestamp),
+        new(big.Int).SetUint64(claim.Expiry),
+    ))
+
+    return crypto.Keccak256Hash([]byte("\x19\x01"), separator[:], structHash[:])
+}
+
+func TestAttestationDigestMatchesContract(t *testing.T) {
+    // Pinned so a change to the types or their encoding can't pass unnoticed
+    const want = "0x0b05e92f6b4fde4b4133b8941382558759c424a12f37b3a311e6ed643cfd5b83"
+
+    digest, err := AttestationDigest(testDomain, testClaim)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if digest.Hex() != want {
+        t.Errorf("digest %s, want %s", digest.Hex(), want)
+    }
+    if onchain := solidityDigest(t, testDomain, testClaim); digest != onchain {
+        t.Errorf("digest %s, contract computes %s", digest.Hex(), onchain.Hex())
+    }
+
+    // Every domain field is bound into the digest
+    for name, domain := range map[string]AttestationDomain{
+        "name":     {Name: "Other", Version: testDomain.Version, ChainID: testDomain.ChainID, VerifyingContract: testDomain.VerifyingContract},
+        "version":  {Name: testDomain.Name, Version: "2", ChainID: testDomain.ChainID, VerifyingContract: testDomain.VerifyingContract},
+        "chain":    {Name: testDomain.Name, Version: testDomain.Version, ChainID: big.NewInt(8453), VerifyingContract: testDomain.VerifyingContract},
+        "contract": {Name: testDomain.Name, Version: testDomain.Version, ChainID: testDomain.ChainID, VerifyingContract: common.HexToAddress("0x1")},
+    } {
+        other, err := AttestationDigest(domain, testClaim)
+        if err != nil {
+            t.Fatal(err)
+        }
+        if other == digest {
+            t.Errorf("changing the domain %s leaves the digest unchanged", name)
+        }
+    }
+}
+
+func TestVerifyAttestation(t *testing.T) {
+    key := mustKey(t, clefKey)
+    signature, err := SignAttestation(key, testDomain, testClaim)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if v := common.FromHex(signature)[crypto.RecoveryIDOffset]; v != 27 && v != 28 {
+        t.Errorf("signature V = %d, want 27 or 28", v)
+    }
+    issued := time.Unix(int64(testClaim.Timestamp), 0)
+
+    tampered := testClaim
+    tampered.Eligible = false
+
+    tests := []struct {
+        name     string
+        domain   AttestationDomain
+        claim    AttestationClaim
+        expected common.Address
+        now      time.Time
+        wantErr  string
+    }{
+        {name: "valid", domain: testDomain, claim: testClaim, expected: key.Address(), now: issued},
+        {name: "valid at expiry", domain: testDomain, claim: testClaim, expected: key.Address(), now: time.Unix(int64(testClaim.Expiry), 0)},
+        {name: "wrong signer", domain: testDomain, claim: testClaim, expected: mustKey(t, otherKey).Address(), now: issued, wantErr: "attestation signed by " + key.Address().Hex()},
+        {name: "tampered claim", domain: testDomain, claim: tampered, expected: key.Address(), now: issued, wantErr: "attestation signed by"},
+        {name: "other chain", domain: AttestationDomain{Name: testDomain.Name, Version: testDomain.Version, ChainID: big.NewInt(1), VerifyingContract: testDomain.VerifyingContract}, claim: testClaim, expected: key.Address(), now: issued, wantErr: "attestation signed by"},
+        {name: "expired", domain: testDomain, claim: testClaim, expected: key.Address(), now: time.Unix(int64(testClaim.Expiry)+1, 0), wantErr: "attestation expired at 2023-12-14T22:13:20Z"},
+    }
+    for _, tc := range tests {
+        t.Run(tc.name, func(t *testing.T) {
+            err := VerifyAttestation(tc.domain, tc.claim, signature, tc.expected, tc.now)
+            if tc.wantErr == "" {
+                if err != nil {
+                    t.Fatal(err)
+                }
+                return
+            }
+            if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+                t.Fatalf("got %v, want %q", err, tc.wantErr)
+            }
+        })
+    }
+}
+
+func TestVerifyAttestationRejectsMalformedSignatures(t *testing.T) {
+    key := mustKey(t, clefKey)
+    issued := time.Unix(int64(testClaim.Timestamp), 0)
+    for _, signature := range []string{"", "0x1234", "not hex", "0x" + strings.Repeat("00", 65)} {
+        if err := VerifyAttestation(testDomain, testClaim, signature, key.Address(), issued); err == nil {
+            t.Errorf("accepted signature %q", signature)
+        }
+    }
+}
@@ -0,0 +1,163 @@
+package crypto
+
+import (
+    "bytes"
+    "context"
+    "fmt"
+    "math/big"
+    "time"
+    "github.com/ethereum/go-ethereum/common"
+    "github.com/ethereum/go-ethereum/common/hexutil"
+    ethtypes "github.com/ethereum/go-ethereum/core/types"
+    "github.com/ethereum/go-ethereum/rpc"
+    "github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// ExternalSigner delegates signing to a Clef-compatible JSON-RPC signer
+// (account_list, account_signData, account_signTypedData,
+// account_signTransaction). The key never enters this process.
+type ExternalSigner struct {
+    client  *rpc.Client
+    address common.Address
+    timeout time.Duration // Clef may wait on a human to approve
+}
+
+// NewExternalSigner connects to endpoint (http(s), ws or IPC path). If address is
+// empty the signer's first account is used.
+func NewExternalSigner(endpoint, address string, timeout time.Duration) (*ExternalSigner, error) {
+    ctx, cancel := context.WithTimeout(context.Background(), timeout)
+    defer cancel()
+
+    client, err := rpc.DialContext(ctx, endpoint)
+    if err != nil {
+        return nil, fmt.Errorf("failed to connect to external signer: %v", err)
+    }
+
+    var accounts []common.Address
+    if err := client.CallContext(ctx, &accounts, "account_list"); err != nil {
+        client.Close()
+        return nil, fmt.Errorf("failed to list signer accounts: %v", err)
+    }
+
+    s := &ExternalSigner{client: client, timeout: timeout}
+    switch {
+    case address != "":
+        if !common.IsHexAddress(address) {
+            client.Close()
+            return nil, fmt.Errorf("invalid signer address %q", address)
+        }
+        s.address = common.HexToAddress(address)
+        found := false
+        for _, a := range accounts {
+            found = found || a == s.address
+        }
+        if !found {
+            client.Close()
+            return nil, fmt.Errorf("external signer does not manage %s", s.address.Hex())
+        }
+    case len(accounts) > 0:
+        s.address = accounts[0]
+    default:
+        client.Close()
+        return nil, fmt.Errorf("external signer has no accounts")
+    }
+    return s, nil
+}
+
+func (s *ExternalSigner) Address() common.Address {
+    return s.address
+}
+
+func (s *ExternalSigner) SignText(data []byte) ([]byte, error) {
+    ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+    defer cancel()
+
+    var signature hexutil.Bytes
+    err := s.client.CallContext(ctx, &signature, "account_signData",
+        "text/plain", common.NewMixedcaseAddress(s.address), hexutil.Encode(data))
+    if err != nil {
+        return nil, fmt.Errorf("external signer refused data: %v", err)
+    }
+    return signature, nil
+}
+
+func (s *ExternalSigner) SignTypedData(data apitypes.TypedData) ([]byte, error) {
+    ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+    defer cancel()
+
+    var signature hexutil.Bytes
+    err := s.client.CallContext(ctx, &signature, "account_signTypedData",
+        common.NewMixedcaseAddress(s.address), data)
+    if err != nil {
+        return nil, fmt.Errorf("external signer refused typed data: %v", err)
+    }
+    return signature, nil
+}
+
+func (s *ExternalSigner) SignTx(tx *ethtypes.Transaction, chainID *big.Int) (*ethtypes.Transaction, error) {
+    ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+    defer cancel()
+
+    data := hexutil.Bytes(tx.Data())
+    args := apitypes.SendTxArgs{
+        From:    common.NewMixedcaseAddress(s.address),
+        Gas:     hexutil.Uint64(tx.Gas()),
+        Value:   hexutil.Big(*tx.Value()),
+        Nonce:   hexutil.Uint64(tx.Nonce()),
+        Input:   &data,
+        ChainID: (*hexutil.Big)(chainID),
+    }
+    if to := tx.To(); to != nil {
+        mixed := common.NewMixedcaseAddress(*to)
+        args.To = &mixed
+    }
+    if tx.Type() == ethtypes.LegacyTxType {
+        args.GasPrice = (*hexutil.Big)(tx.GasPrice())
+    } else {
+        args.MaxFeePerGas = (*hexutil.Big)(tx.GasFeeCap())
+        args.MaxPriorityFeePerGas = (*hexutil.Big)(tx.GasTipCap())
+    }
+
+    var result struct {
+        Raw hexutil.Bytes `json:"raw"`
+    }
+    if err := s.client.CallContext(ctx, &result, "account_signTransaction", args); err != nil {
+        return nil, fmt.Errorf("external signer refused transaction: %v", err)
+    }
+
+    signed := new(ethtypes.Transaction)
+    if err := signed.UnmarshalBinary(result.Raw); err != nil {
+        return nil, fmt.Errorf("invalid signed transaction from external signer: %v", err)
+    }
+    // Guard against a signer that altered what we asked it to sign, or signed
+    // it with another key
+    if !sameCall(signed, tx, chainID) {
+        return nil, fmt.Errorf("external signer returned a different transaction")
+    }
+    from, err := ethtypes.Sender(ethtypes.LatestSignerForChainID(chainID), signed)
+    if err != nil {
+        return nil, fmt.Errorf("invalid signature from external signer: %v", err)
+    }
+    if from != s.address {
+        return nil, fmt.Errorf("external signer signed as %s, expected %s", from.Hex(), s.address.Hex())
+    }
+    return signed, nil
+}
+
+// sameCall reports whether signed carries the same call and fees as tx, for chainID
+func sameCall(signed, tx *ethtypes.Transaction, chainID *big.Int) bool {
+    a, b := signed, tx
+    if a.Type() != b.Type() || a.ChainId().Cmp(chainID) != 0 {
+        return false
+    }
+    if a.Nonce() != b.Nonce() || a.Gas() != b.Gas() || a.Value().Cmp(b.Value()) != 0 || !bytes.Equal(a.Data(), b.Data()) {
+        return false
+    }
+    if a.GasPrice().Cmp(b.GasPrice()) != 0 || a.GasFeeCap().Cmp(b.GasFeeCap()) != 0 || a.GasTipCap().Cmp(b.GasTipCap()) != 0 {
+        return false
+    }
+    if a.To() == nil || b.To() == nil {
+        return a.To() == nil && b.To() == nil
+    }
+    return *a.To() == *b.To()
+}
@@ -0,0 +1,225 @@
+package crypto
+
+import (
+    "math/big"
+    "net/http/httptest"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/ethereum/go-ethereum/common"
+    "github.com/ethereum/go-ethereum/common/hexutil"
+    ethtypes "github.com/ethereum/go-ethereum/core/types"
+    "github.com/ethereum/go-ethereum/rpc"
+    "github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+const (
+    clefKey  = "b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291"
+    otherKey = "8a1f9a8f95be41cd7ccb6168179afb4504aefe388d1e14474d32c45c72ce7b7a"
+)
+
+// clefStub answers the account_* methods Clef exposes, signing with key
+type clefStub struct {
+    key *KeySigner
+    // Misbehaviour under test: tamper edits the request before signing, txKey
+    // signs transactions with a different key
+    tamper func(args *apitypes.SendTxArgs)
+    txKey  *KeySigner
+}
+
+func (c *clefStub) List() []common.Address {
+    return []common.Address{c.key.Address()}
+}
+
+func (c *clefStub) SignData(contentType string, addr common.MixedcaseAddress, data hexutil.Bytes) (hexutil.Bytes, error) {
+    return c.key.SignText(data)
+}
+
+func (c *clefStub) SignTypedData(addr common.MixedcaseAddress, data apitypes.TypedData) (hexutil.Bytes, error) {
+    return c.key.SignTypedData(data)
+}
+
+func (c *clefStub) SignTransaction(args apitypes.SendTxArgs) (map[string]interface{}, error) {
+    if c.tamper != nil {
+        c.tamper(&args)
+    }
+    tx, err := args.ToTransaction()
+    if err != nil {
+        return nil, err
+    }
+    key := c.key
+    if c.txKey != nil {
+        key = c.txKey
+    }
+    signed, err := key.SignTx(tx, (*big.Int)(args.ChainID))
+    if err != nil {
+        return nil, err
+    }
+    raw, err := signed.MarshalBinary()
+    if err != nil {
+        return nil, err
+    }
+    return map[string]interface{}{"raw": hexutil.Bytes(raw), "tx": signed}, nil
+}
+
+func newClefStub(t *testing.T, stub *clefStub) *ExternalSigner {
+    t.Helper()
+    server := rpc.NewServer()
+    if err := server.RegisterName("account", stub); err != nil {
+        t.Fatal(err)
+    }
+    httpServer := httptest.NewServer(server)
+    t.Cleanup(func() {
+        httpServer.Close()
+        server.Stop()
+    })
+
+    s, err := NewExternalSigner(httpServer.URL, "", 5*time.Second)
+    if err != nil {
+        t.Fatal(err)
+    }
+    return s
+}
+
+func mustKey(t *testing.T, hex string) *KeySigner {
+    t.Helper()
+    key, err := NewKeySigner(hex)
+    if err != nil {
+        t.Fatal(err)
+    }
+    return key
+}
+
+func TestExternalSignerSignsData(t *testing.T) {
+    key := mustKey(t, clefKey)
+    s := newClefStub(t, &clefStub{key: key})
+    if s.Address() != key.Address() {
+        t.Fatalf("address = %s, want %s", s.Address().Hex(), key.Address().Hex())
+    }
+
+    payload := []byte(`{"event":"verification.completed"}`)
+    signature, err := SignPayload(s, payload)
+    if err != nil {
+        t.Fatal(err)
+    }
+    signer, err := RecoverText(payload, signature)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if signer != key.Address() {
+        t.Errorf("text signed by %s, want %s", signer.Hex(), key.Address().Hex())
+    }
+}
+
+func TestExternalSignerSignsTypedData(t *testing.T) {
+    key := mustKey(t, clefKey)
+    s := newClefStub(t, &clefStub{key: key})
+
+    domain := AttestationDomain{Name: "PropToken Oracle", Version: "1", ChainID: big.NewInt(84532), VerifyingContract: common.HexToAddress("0x1")}
+    claim := AttestationClaim{
+        Fingerprint:    common.HexToHash("0xaa"),
+        MerkleRoot:     common.HexToHash("0xbb"),
+        ExistenceScore: big.NewInt(9e17),
+        OwnershipScore: big.NewInt(8e17),
+        ActivityScore:  big.NewInt(7e17),
+        Eligible:       true,
+        Timestamp:      1700000000,
+        Expiry:         1700086400,
+    }
+    signature, err := SignAttestation(s, domain, claim)
+    if err != nil {
+        t.Fatal(err)
+    }
+    signer, err := RecoverAttestationSigner(domain, claim, signature)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if signer != key.Address() {
+        t.Errorf("attestation signed by %s, want %s", signer.Hex(), key.Address().Hex())
+    }
+}
+
+func TestExternalSignerSignsTransaction(t *testing.T) {
+    chainID := big.NewInt(84532)
+    to := common.HexToAddress("0x00000000000000000000000000000000000000AA")
+    dynamic := ethtypes.NewTx(&ethtypes.DynamicFeeTx{
+        ChainID:   chainID,
+        Nonce:     7,
+        GasTipCap: big.NewInt(1e9),
+        GasFeeCap: big.NewInt(3e9),
+        Gas:       90000,
+        To:        &to,
+        Value:     big.NewInt(0),
+        Data:      []byte{0xde, 0xad, 0xbe, 0xef},
+    })
+    legacy := ethtypes.NewTransaction(7, to, big.NewInt(1), 21000, big.NewInt(2e9), nil)
+
+    cases := []struct {
+        name    string
+        tx      *ethtypes.Transaction
+        chainID *big.Int
+        stub    clefStub
+        wantErr string
+    }{
+        {name: "dynamic fee", tx: dynamic, chainID: chainID},
+        {name: "legacy", tx: legacy, chainID: chainID},
+        {
+            name: "altered fee cap", tx: dynamic, chainID: chainID,
+            stub:    clefStub{tamper: func(args *apitypes.SendTxArgs) { args.MaxFeePerGas = (*hexutil.Big)(big.NewInt(9e9)) }},
+            wantErr: "different transaction",
+        },
+        {
+            name: "altered tip", tx: dynamic, chainID: chainID,
+            stub:    clefStub{tamper: func(args *apitypes.SendTxArgs) { args.MaxPriorityFeePerGas = (*hexutil.Big)(big.NewInt(2e9)) }},
+            wantErr: "different transaction",
+        },
+        {
+            name: "altered gas price", tx: legacy, chainID: chainID,
+            stub:    clefStub{tamper: func(args *apitypes.SendTxArgs) { args.GasPrice = (*hexutil.Big)(big.NewInt(5e9)) }},
+            wantErr: "different transaction",
+        },
+        {
+            name: "other chain", tx: dynamic, chainID: chainID,
+            stub:    clefStub{tamper: func(args *apitypes.SendTxArgs) { args.ChainID = (*hexutil.Big)(big.NewInt(1)) }},
+            wantErr: "different transaction",
+        },
+        {
+            name: "downgraded to legacy", tx: dynamic, chainID: chainID,
+            stub: clefStub{tamper: func(args *apitypes.SendTxArgs) {
+                args.GasPrice, args.MaxFeePerGas, args.MaxPriorityFeePerGas = args.MaxFeePerGas, nil, nil
+            }},
+            wantErr: "different transaction",
+        },
+        {
+            name: "wrong key", tx: dynamic, chainID: chainID,
+            stub:    clefStub{txKey: mustKey(t, otherKey)},
+            wantErr: "signed as",
+        },
+    }
+    for _, tc := range cases {
+        t.Run(tc.name, func(t *testing.T) {
+            stub := tc.stub
+            stub.key = mustKey(t, clefKey)
+            s := newClefStub(t, &stub)
+
+            signed, err := s.SignTx(tc.tx, tc.chainID)
+            if tc.wantErr != "" {
+                if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+                    t.Fatalf("err = %v, want %q", err, tc.wantErr)
+                }
+                return
+            }
+            if err != nil {
+                t.Fatal(err)
+            }
+            from, err := ethtypes.Sender(ethtypes.LatestSignerForChainID(tc.chainID), signed)
+            if err != nil {
+                t.Fatal(err)
+            }
+            if from != stub.key.Address() || signed.Nonce() != tc.tx.Nonce() || signed.Type() != tc.tx.Type() {
+                t.Errorf("signed tx from %s nonce %d type %d", from.Hex(), signed.Nonce(), signed.Type())
+            }
+        })
+    }
+}
@@ -0,0 +1,35 @@
+package crypto
+
+import (
+    "fmt"
+    "github.com/ethereum/go-ethereum/common"
+)
+
+// Accounts whose private keys are public: trivial scalars and the default
+// Hardhat/Anvil mnemonic ("test test ... junk")
+var knownTestAccounts = map[common.Address]string{
+    common.HexToAddress("0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf"): "private key 0x...01",
+    common.HexToAddress("0x2B5AD5c4795c026514f8317c7a215E218DcCD6cF"): "private key 0x...02",
+    common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"): "Hardhat/Anvil account #0",
+    common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8"): "Hardhat/Anvil account #1",
+    common.HexToAddress("0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC"): "Hardhat/Anvil account #2",
+    common.HexToAddress("0x90F79bf6EB2c4f870365E785982E1f101E93b906"): "Hardhat/Anvil account #3",
+    common.HexToAddress("0x15d34AAf54267DB7D7c367839AAf71A00a2C6A65"): "Hardhat/Anvil account #4",
+    common.HexToAddress("0x9965507D1a55bcC2695C58ba16FB37d819B0A4dc"): "Hardhat/Anvil account #5",
+    common.HexToAddress("0x976EA74026E726554dB657fA54763abd0C3a0aa9"): "Hardhat/Anvil account #6",
+    common.HexToAddress("0x14dC79964da2C08b23698B3D3cc7Ca32193d9955"): "Hardhat/Anvil account #7",
+    common.HexToAddress("0x23618e81E3f5cdF7f54C3d65f7FBc0aBf5B21E8f"): "Hardhat/Anvil account #8",
+    common.HexToAddress("0xa0Ee7A142d267C1f36714E4a8F75612F20a79720"): "Hardhat/Anvil account #9",
+}
+
+// CheckProductionKey rejects signers whose key is publicly known or, for
+// in-memory keys, has too little entropy to have been randomly generated
+func CheckProductionKey(s Signer) error {
+    if name, ok := knownTestAccounts[s.Address()]; ok {
+        return fmt.Errorf("signer %s is the well-known %s", s.Address().Hex(), name)
+    }
+    if ks, ok := s.(*KeySigner); ok && ks.PrivateKey.D.BitLen() < 128 {
+        return fmt.Errorf("signer %s has a weak private key (%d bits)", s.Address().Hex(), ks.PrivateKey.D.BitLen())
+    }
+    return nil
+}
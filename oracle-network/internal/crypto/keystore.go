@@ -0,0 +1,28 @@
+package crypto
+
+import (
+    "fmt"
+    "os"
+    "strings"
+    "github.com/ethereum/go-ethereum/accounts/keystore"
+)
+
+// NewKeystoreSigner decrypts a go-ethereum keystore JSON file with the passphrase
+// read from passphraseFile (trailing newline ignored), so the key never has to be
+// passed through the environment.
+func NewKeystoreSigner(keyfile, passphraseFile string) (*KeySigner, error) {
+    keyJSON, err := os.ReadFile(keyfile)
+    if err != nil {
+        return nil, fmt.Errorf("failed to read keystore %s: %v", keyfile, err)
+    }
+    passphrase, err := os.ReadFile(passphraseFile)
+    if err != nil {
+        return nil, fmt.Errorf("failed to read passphrase file: %v", err)
+    }
+
+    key, err := keystore.DecryptKey(keyJSON, strings.TrimRight(string(passphrase), "\r\n"))
+    if err != nil {
+        return nil, fmt.Errorf("failed to decrypt keystore %s: %v", keyfile, err)
+    }
+    return &KeySigner{PrivateKey: key.PrivateKey}, nil
+}
@@ -1,34 +1,121 @@
 package crypto
 
 import (
-    "crypto/sha256"
-    "encoding/hex"
+    "bytes"
     "sort"
+
+    "github.com/ethereum/go-ethereum/common"
+    "github.com/ethereum/go-ethereum/common/hexutil"
+    "github.com/ethereum/go-ethereum/crypto"
 )
 
-// GenerateMerkleRoot creates a simple root hash from a list of data strings
-// This is a simplified implementation for the demo
-func GenerateMerkleRoot(data []string) string {
+// MerkleTree is a binary keccak256 tree over a set of leaves.
+// Pairs are sorted before hashing, matching OpenZeppelin's MerkleProof,
+// so a proof can be checked on-chain with MerkleProof.verify(proof, root, leafHash).
+type MerkleTree struct {
+    Root   common.Hash
+    leaves map[string]common.Hash
+    levels [][]common.Hash
+}
+
+// HashLeaf returns the keccak256 hash used for a leaf's data
+func HashLeaf(data string) common.Hash {
+    return crypto.Keccak256Hash([]byte(data))
+}
+
+// hashPair hashes two nodes in sorted order (commutative)
+func hashPair(a, b common.Hash) common.Hash {
+    if bytes.Compare(a[:], b[:]) > 0 {
+        a, b = b, a
+    }
+    return crypto.Keccak256Hash(a[:], b[:])
+}
+
+// NewMerkleTree builds the tree bottom-up. Leaf hashes are sorted for a
+// deterministic layout; an odd node at the end of a level is promoted unchanged.
+func NewMerkleTree(data []string) *MerkleTree {
+    tree := &MerkleTree{leaves: make(map[string]common.Hash)}
     if len(data) == 0 {
-        return ""
+        return tree
     }
 
-    // Leaf hashes
-    var leaves []string
+    level := make([]common.Hash, 0, len(data))
     for _, d := range data {
-        hash := sha256.Sum256([]byte(d))
-        leaves = append(leaves, hex.EncodeToString(hash[:]))
+        h := HashLeaf(d)
+        tree.leaves[d] = h
+        level = append(level, h)
+    }
+    sort.Slice(level, func(i, j int) bool {
+        return bytes.Compare(level[i][:], level[j][:]) < 0
+    })
+    tree.levels = append(tree.levels, level)
+
+    for len(level) > 1 {
+        next := make([]common.Hash, 0, (len(level)+1)/2)
+        for i := 0; i < len(level); i += 2 {
+            if i+1 == len(level) {
+                next = append(next, level[i])
+                continue
+            }
+            next = append(next, hashPair(level[i], level[i+1]))
+        }
+        tree.levels = append(tree.levels, next)
+        level = next
+    }
+
+    tree.Root = level[0]
+    return tree
+}
+
+// Proof returns the sibling path from the leaf to the root.
+// ok is false if the data is not a leaf of this tree.
+func (t *MerkleTree) Proof(data string) (proof []common.Hash, ok bool) {
+    leaf, ok := t.leaves[data]
+    if !ok {
+        return nil, false
+    }
+
+    index := -1
+    for i, h := range t.levels[0] {
+        if h == leaf {
+            index = i
+            break
+        }
+    }
+
+    proof = []common.Hash{}
+    for _, level := range t.levels[:len(t.levels)-1] {
+        sibling := index ^ 1
+        if sibling < len(level) {
+            proof = append(proof, level[sibling])
+        }
+        index /= 2
+    }
+    return proof, true
+}
+
+// VerifyMerkleProof checks that leaf hashes up to root along proof
+func VerifyMerkleProof(root, leaf common.Hash, proof []common.Hash) bool {
+    computed := leaf
+    for _, p := range proof {
+        computed = hashPair(computed, p)
     }
-    sort.Strings(leaves) // Deterministic order
+    return computed == root
+}
+
+// GenerateMerkleRoot returns the hex-encoded root for a list of data strings
+func GenerateMerkleRoot(data []string) string {
+    if len(data) == 0 {
+        return ""
+    }
+    return NewMerkleTree(data).Root.Hex()
+}
 
-    // Build tree levels (simplified: just hash all leaves together for level 1 in this robust mock)
-    // In a full implementation, you'd pair and hash up to the root.
-    // For this prototype, a linear hash of sorted leaves serves the cryptographic commitment purpose.
-    combined := ""
-    for _, l := range leaves {
-        combined += l
+// EncodeProof hex-encodes a proof path
+func EncodeProof(proof []common.Hash) []string {
+    encoded := make([]string, len(proof))
+    for i, p := range proof {
+        encoded[i] = hexutil.Encode(p[:])
     }
-    
-    root := sha256.Sum256([]byte(combined))
-    return hex.EncodeToString(root[:])
+    return encoded
 }
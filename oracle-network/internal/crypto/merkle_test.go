@@ -0,0 +1,126 @@
+package crypto
+
+import (
+    "bytes"
+    "fmt"
+    "testing"
+
+    "github.com/ethereum/go-ethereum/common"
+    "github.com/ethereum/go-ethereum/crypto"
+)
+
+// ozProcessProof mirrors OpenZeppelin MerkleProof.processProof: fold the proof
+// into the leaf with keccak256 of the sorted pair (Hashes.commutativeKeccak256)
+func ozProcessProof(proof []common.Hash, leaf common.Hash) common.Hash {
+    computed := leaf
+    for _, p := range proof {
+        a, b := computed, p
+        if bytes.Compare(a[:], b[:]) > 0 {
+            a, b = b, a
+        }
+        computed = crypto.Keccak256Hash(append(append([]byte{}, a[:]...), b[:]...))
+    }
+    return computed
+}
+
+func ozVerify(proof []common.Hash, root, leaf common.Hash) bool {
+    return ozProcessProof(proof, leaf) == root
+}
+
+func leafData(n int) []string {
+    data := make([]string, n)
+    for i := range data {
+        data[i] = fmt.Sprintf("leaf-%d", i)
+    }
+    return data
+}
+
+func TestMerkleProofsVerifyLikeOpenZeppelin(t *testing.T) {
+    cases := []struct {
+        name string
+        data []string
+    }{
+        {"single leaf", []string{"only"}},
+        {"two leaves", leafData(2)},
+        {"odd count", leafData(3)},
+        {"odd count, deeper", leafData(7)},
+        {"power of two", leafData(8)},
+        {"duplicate leaves", []string{"a", "b", "a", "c"}},
+    }
+    for _, tc := range cases {
+        t.Run(tc.name, func(t *testing.T) {
+            tree := NewMerkleTree(tc.data)
+            for _, d := range tc.data {
+                proof, ok := tree.Proof(d)
+                if !ok {
+                    t.Fatalf("no proof for %q", d)
+                }
+                leaf := HashLeaf(d)
+                if !ozVerify(proof, tree.Root, leaf) {
+                    t.Errorf("OpenZeppelin verify rejects proof for %q", d)
+                }
+                if !VerifyMerkleProof(tree.Root, leaf, proof) {
+                    t.Errorf("VerifyMerkleProof rejects proof for %q", d)
+                }
+            }
+        })
+    }
+}
+
+func TestMerkleRootLayout(t *testing.T) {
+    sorted := func(data ...string) []common.Hash {
+        hs := make([]common.Hash, len(data))
+        for i, d := range data {
+            hs[i] = HashLeaf(d)
+        }
+        for i := range hs {
+            for j := i + 1; j < len(hs); j++ {
+                if bytes.Compare(hs[j][:], hs[i][:]) < 0 {
+                    hs[i], hs[j] = hs[j], hs[i]
+                }
+            }
+        }
+        return hs
+    }
+
+    // A single leaf is its own root, with an empty proof
+    single := NewMerkleTree([]string{"only"})
+    if single.Root != HashLeaf("only") {
+        t.Errorf("single-leaf root = %s, want the leaf hash", single.Root.Hex())
+    }
+    if proof, _ := single.Proof("only"); len(proof) != 0 {
+        t.Errorf("single-leaf proof has %d nodes, want 0", len(proof))
+    }
+
+    // Odd counts promote the last node unchanged: root = H(H(h0,h1), h2)
+    h := sorted("x", "y", "z")
+    want := hashPair(hashPair(h[0], h[1]), h[2])
+    if got := NewMerkleTree([]string{"z", "x", "y"}).Root; got != want {
+        t.Errorf("3-leaf root = %s, want %s", got.Hex(), want.Hex())
+    }
+
+    // Input order doesn't matter
+    if a, b := NewMerkleTree([]string{"x", "y", "z"}).Root, NewMerkleTree([]string{"y", "z", "x"}).Root; a != b {
+        t.Errorf("root depends on input order: %s vs %s", a.Hex(), b.Hex())
+    }
+}
+
+func TestMerkleProofRejects(t *testing.T) {
+    tree := NewMerkleTree(leafData(5))
+    proof, _ := tree.Proof("leaf-2")
+
+    if VerifyMerkleProof(tree.Root, HashLeaf("leaf-9"), proof) {
+        t.Error("proof verified for a leaf not in the tree")
+    }
+    tampered := append([]common.Hash{}, proof...)
+    tampered[0][0] ^= 1
+    if VerifyMerkleProof(tree.Root, HashLeaf("leaf-2"), tampered) {
+        t.Error("tampered proof verified")
+    }
+    if _, ok := tree.Proof("leaf-9"); ok {
+        t.Error("Proof returned ok for a leaf not in the tree")
+    }
+    if GenerateMerkleRoot(nil) != "" {
+        t.Error("empty tree has a root")
+    }
+}
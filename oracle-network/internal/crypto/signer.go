@@ -3,31 +3,84 @@ package crypto
 import (
     "crypto/ecdsa"
     "fmt"
+    "math/big"
+    "strings"
+    "github.com/ethereum/go-ethereum/accounts"
+    "github.com/ethereum/go-ethereum/common"
     "github.com/ethereum/go-ethereum/common/hexutil"
+    ethtypes "github.com/ethereum/go-ethereum/core/types"
     "github.com/ethereum/go-ethereum/crypto"
+    "github.com/ethereum/go-ethereum/signer/core/apitypes"
 )
 
-type Signer struct {
+// Signer holds the oracle's identity. Attestations, webhooks and registry
+// transactions are all signed through it, so the key can live in memory (dev),
+// an encrypted keystore, or an external signer such as Clef.
+type Signer interface {
+    Address() common.Address
+    // SignText signs data as an EIP-191 personal message; V is 27/28
+    SignText(data []byte) ([]byte, error)
+    // SignTypedData signs EIP-712 typed data; V is 27/28
+    SignTypedData(data apitypes.TypedData) ([]byte, error)
+    SignTx(tx *ethtypes.Transaction, chainID *big.Int) (*ethtypes.Transaction, error)
+}
+
+// KeySigner keeps a raw private key in memory. Intended for development.
+type KeySigner struct {
     PrivateKey *ecdsa.PrivateKey
 }
 
-func NewSigner(privateKeyHex string) (*Signer, error) {
-    privateKey, err := crypto.HexToECDSA(privateKeyHex[2:]) // Remove 0x
+func NewKeySigner(privateKeyHex string) (*KeySigner, error) {
+    privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(privateKeyHex, "0x"))
     if err != nil {
         return nil, err
     }
-    return &Signer{PrivateKey: privateKey}, nil
+    return &KeySigner{PrivateKey: privateKey}, nil
+}
+
+// Address returns the oracle's Ethereum address
+func (s *KeySigner) Address() common.Address {
+    return crypto.PubkeyToAddress(s.PrivateKey.PublicKey)
 }
 
-// SignAttestation signs the (Root + SubmissionID) hash
-func (s *Signer) SignAttestation(merkleRoot, submissionID string) (string, error) {
-    message := fmt.Sprintf("Submission:%s|Root:%s", submissionID, merkleRoot)
-    hash := crypto.Keccak256Hash([]byte(message))
-    
-    signature, err := crypto.Sign(hash.Bytes(), s.PrivateKey)
+func (s *KeySigner) SignText(data []byte) ([]byte, error) {
+    signature, err := crypto.Sign(accounts.TextHash(data), s.PrivateKey)
+    if err != nil {
+        return nil, err
+    }
+    signature[crypto.RecoveryIDOffset] += 27 // Ethereum-style V
+    return signature, nil
+}
+
+func (s *KeySigner) SignTypedData(data apitypes.TypedData) ([]byte, error) {
+    hash, _, err := apitypes.TypedDataAndHash(data)
+    if err != nil {
+        return nil, fmt.Errorf("failed to hash typed data: %v", err)
+    }
+    signature, err := crypto.Sign(hash, s.PrivateKey)
+    if err != nil {
+        return nil, err
+    }
+    signature[crypto.RecoveryIDOffset] += 27
+    return signature, nil
+}
+
+func (s *KeySigner) SignTx(tx *ethtypes.Transaction, chainID *big.Int) (*ethtypes.Transaction, error) {
+    return ethtypes.SignTx(tx, ethtypes.LatestSignerForChainID(chainID), s.PrivateKey)
+}
+
+// SignPayload signs arbitrary bytes as an EIP-191 personal message (e.g. webhook bodies),
+// so receivers can recover the oracle address with standard wallet tooling
+func SignPayload(s Signer, payload []byte) (string, error) {
+    signature, err := s.SignText(payload)
     if err != nil {
         return "", err
     }
-    
+
     return hexutil.Encode(signature), nil
 }
+
+// RecoverText returns the signer of an EIP-191 signature over data
+func RecoverText(data []byte, signature string) (common.Address, error) {
+    return recoverDigest(accounts.TextHash(data), signature)
+}
@@ -0,0 +1,275 @@
+// Package geo checks submission coordinates against administrative boundaries
+// and land use. The bundled GeoJSON is a coarse outline of the states and
+// cities the oracle currently serves, plus known water bodies and protected
+// areas around them; operators can point it at fuller data sets.
+package geo
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/yourorg/proptoken-oracle/pkg/types"
+)
+
+//go:embed data/*.geojson
+var bundled embed.FS
+
+// Boundary levels and land use classes in the GeoJSON properties
+const (
+	LevelState = "state"
+	LevelCity  = "city"
+
+	ClassWater        = "water"
+	ClassNonBuildable = "non_buildable"
+)
+
+// Outcome of checking a claimed City or State against the point
+const (
+	MatchOK       = "match"
+	MatchMismatch = "mismatch" // the claimed region is known and the point is outside it
+	MatchUnknown  = "unknown"  // no boundary for the claimed region, or none claimed
+)
+
+// Index holds boundary and land use polygons
+type Index struct {
+	states  []feature
+	cities  []feature
+	landuse []feature
+}
+
+// Report is the outcome of checking one location
+type Report struct {
+	Valid        bool   `json:"valid"` // coordinates are in range
+	Error        string `json:"error,omitempty"`
+	State        string `json:"state,omitempty"` // state containing the point
+	City         string `json:"city,omitempty"`  // city containing the point
+	StateCheck   string `json:"state_check"`
+	CityCheck    string `json:"city_check"`
+	Water        string `json:"water,omitempty"`         // water body containing the point
+	NonBuildable string `json:"non_buildable,omitempty"` // non-buildable area containing the point, with reason
+}
+
+// Buildable reports whether the point is valid, on land and not in a non-buildable area
+func (r Report) Buildable() bool {
+	return r.Valid && r.Water == "" && r.NonBuildable == ""
+}
+
+// Bundled loads the boundaries and land use shipped with the oracle
+func Bundled() (*Index, error) {
+	return LoadFiles("", "")
+}
+
+// LoadFiles loads GeoJSON files; an empty path falls back to the bundled data for that layer
+func LoadFiles(boundariesPath, landusePath string) (*Index, error) {
+	read := func(path, name string) (io.Reader, error) {
+		if path == "" {
+			data, err := bundled.ReadFile("data/" + name)
+			return bytes.NewReader(data), err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %v", path, err)
+		}
+		return bytes.NewReader(data), nil
+	}
+	boundaries, err := read(boundariesPath, "boundaries.geojson")
+	if err != nil {
+		return nil, err
+	}
+	landuse, err := read(landusePath, "landuse.geojson")
+	if err != nil {
+		return nil, err
+	}
+	return Load(boundaries, landuse)
+}
+
+// Load builds an index from boundary and land use FeatureCollections
+func Load(boundaries, landuse io.Reader) (*Index, error) {
+	ix := &Index{}
+	admin, err := readFeatures(boundaries)
+	if err != nil {
+		return nil, fmt.Errorf("boundaries: %v", err)
+	}
+	for _, f := range admin {
+		switch f.props.Level {
+		case LevelState:
+			ix.states = append(ix.states, f)
+		case LevelCity:
+			ix.cities = append(ix.cities, f)
+		default:
+			return nil, fmt.Errorf("boundaries: %s has level %q, want %s or %s", f.props.Name, f.props.Level, LevelState, LevelCity)
+		}
+	}
+
+	ix.landuse, err = readFeatures(landuse)
+	if err != nil {
+		return nil, fmt.Errorf("land use: %v", err)
+	}
+	for _, f := range ix.landuse {
+		if f.props.Class != ClassWater && f.props.Class != ClassNonBuildable {
+			return nil, fmt.Errorf("land use: %s has class %q, want %s or %s", f.props.Name, f.props.Class, ClassWater, ClassNonBuildable)
+		}
+	}
+	return ix, nil
+}
+
+// ValidCoordinates checks c is a real point on the globe. 0,0 is rejected as
+// the usual sign of unset coordinates.
+func ValidCoordinates(c types.Coordinates) error {
+	if math.IsNaN(c.Lat) || math.IsNaN(c.Lng) || math.IsInf(c.Lat, 0) || math.IsInf(c.Lng, 0) {
+		return fmt.Errorf("coordinates are not finite")
+	}
+	if c.Lat < -90 || c.Lat > 90 {
+		return fmt.Errorf("latitude %v not in [-90,90]", c.Lat)
+	}
+	if c.Lng < -180 || c.Lng > 180 {
+		return fmt.Errorf("longitude %v not in [-180,180]", c.Lng)
+	}
+	if c.Lat == 0 && c.Lng == 0 {
+		return fmt.Errorf("coordinates 0,0 are unset")
+	}
+	return nil
+}
+
+// Check validates loc's coordinates and compares them with its claimed City and State
+func (ix *Index) Check(loc types.Location) Report {
+	if err := ValidCoordinates(loc.Coordinates); err != nil {
+		return Report{Error: err.Error(), StateCheck: MatchUnknown, CityCheck: MatchUnknown}
+	}
+	lng, lat := loc.Coordinates.Lng, loc.Coordinates.Lat
+
+	r := Report{Valid: true}
+	var state, city *feature
+	r.StateCheck, state = check(ix.states, loc.State, lng, lat)
+	r.CityCheck, city = check(ix.citiesIn(loc.State), loc.City, lng, lat)
+	if r.CityCheck != MatchOK {
+		// Report the city the point is in, whatever state was claimed
+		city = locate(ix.cities, lng, lat)
+	}
+	if state != nil {
+		r.State = state.props.Name
+	}
+	if city != nil {
+		r.City = city.props.Name
+	}
+
+	for _, f := range ix.landuse {
+		if !f.contains(lng, lat) {
+			continue
+		}
+		switch f.props.Class {
+		case ClassWater:
+			if r.Water == "" {
+				r.Water = f.props.Name
+			}
+		case ClassNonBuildable:
+			if r.NonBuildable == "" {
+				r.NonBuildable = f.props.Name
+				if f.props.Reason != "" {
+					r.NonBuildable += " (" + f.props.Reason + ")"
+				}
+			}
+		}
+	}
+	return r
+}
+
+// citiesIn returns the cities of the claimed state, so a city name that recurs
+// in several states is only checked against the claimed one. Cities that give
+// no state stay candidates. If no state is claimed, or the claim names no known
+// state, every city is a candidate.
+func (ix *Index) citiesIn(claimed string) []feature {
+	want := normalize(claimed)
+	if want == "" {
+		return ix.cities
+	}
+	var states []feature
+	for _, s := range ix.states {
+		if named(s, want) {
+			states = append(states, s)
+		}
+	}
+	if len(states) == 0 {
+		return ix.cities
+	}
+
+	var cities []feature
+	for _, c := range ix.cities {
+		if c.props.State == "" {
+			cities = append(cities, c)
+			continue
+		}
+		for _, s := range states {
+			if named(s, normalize(c.props.State)) {
+				cities = append(cities, c)
+				break
+			}
+		}
+	}
+	return cities
+}
+
+// locate returns the first feature containing the point, if any
+func locate(features []feature, lng, lat float64) *feature {
+	for i := range features {
+		if features[i].contains(lng, lat) {
+			return &features[i]
+		}
+	}
+	return nil
+}
+
+// check compares claimed against the features containing the point. It
+// returns the outcome and the containing feature, if any.
+func check(features []feature, claimed string, lng, lat float64) (string, *feature) {
+	containing := locate(features, lng, lat)
+
+	want := normalize(claimed)
+	if want == "" {
+		return MatchUnknown, containing
+	}
+	known := false
+	for i := range features {
+		if !named(features[i], want) {
+			continue
+		}
+		known = true
+		if features[i].contains(lng, lat) {
+			return MatchOK, &features[i]
+		}
+	}
+	if !known {
+		return MatchUnknown, containing
+	}
+	return MatchMismatch, containing
+}
+
+// named reports whether f's name or one of its aliases normalizes to name
+func named(f feature, name string) bool {
+	if normalize(f.props.Name) == name {
+		return true
+	}
+	for _, a := range f.props.Aliases {
+		if normalize(a) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// normalize folds case and drops everything but letters and digits, so
+// "Navi-Mumbai" and "navi mumbai" compare equal
+func normalize(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, name)
+}
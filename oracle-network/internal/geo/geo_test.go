@@ -0,0 +1,214 @@
+package geo
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yourorg/proptoken-oracle/pkg/types"
+)
+
+// Two square states side by side, a Springfield in each (Alpha's with a park
+// cut out of it), a triangular city and a city that gives no state
+const testBoundaries = `{
+  "type": "FeatureCollection",
+  "features": [
+    {"type": "Feature", "properties": {"name": "Alpha", "level": "state", "aliases": ["AL"]},
+     "geometry": {"type": "Polygon", "coordinates": [[[10, 10], [20, 10], [20, 20], [10, 20], [10, 10]]]}},
+    {"type": "Feature", "properties": {"name": "Beta", "level": "state", "aliases": ["BT"]},
+     "geometry": {"type": "Polygon", "coordinates": [[[20, 10], [30, 10], [30, 20], [20, 20], [20, 10]]]}},
+    {"type": "Feature", "properties": {"name": "Springfield", "level": "city", "state": "Alpha"},
+     "geometry": {"type": "Polygon", "coordinates": [
+       [[12, 12], [16, 12], [16, 16], [12, 16], [12, 12]],
+       [[13, 13], [14, 13], [14, 14], [13, 14], [13, 13]]
+     ]}},
+    {"type": "Feature", "properties": {"name": "Springfield", "level": "city", "state": "BT"},
+     "geometry": {"type": "Polygon", "coordinates": [[[22, 12], [26, 12], [26, 16], [22, 16], [22, 12]]]}},
+    {"type": "Feature", "properties": {"name": "Port Town", "level": "city", "state": "Alpha", "aliases": ["Harbour-Town"]},
+     "geometry": {"type": "Polygon", "coordinates": [[[16, 11], [19, 11], [19, 14], [16, 11]]]}},
+    {"type": "Feature", "properties": {"name": "Freeport", "level": "city"},
+     "geometry": {"type": "MultiPolygon", "coordinates": [
+       [[[11, 17], [12, 17], [12, 18], [11, 18], [11, 17]]],
+       [[[27, 17], [28, 17], [28, 18], [27, 18], [27, 17]]]
+     ]}}
+  ]
+}`
+
+const testLanduse = `{
+  "type": "FeatureCollection",
+  "features": [
+    {"type": "Feature", "properties": {"name": "Blue Lake", "class": "water"},
+     "geometry": {"type": "Polygon", "coordinates": [[[27, 12], [29, 12], [29, 14], [27, 14], [27, 12]]]}},
+    {"type": "Feature", "properties": {"name": "Old Forest", "class": "non_buildable", "reason": "protected"},
+     "geometry": {"type": "Polygon", "coordinates": [[[17, 17], [19, 17], [19, 19], [17, 19], [17, 17]]]}}
+  ]
+}`
+
+func testIndex(t *testing.T) *Index {
+	t.Helper()
+	ix, err := Load(strings.NewReader(testBoundaries), strings.NewReader(testLanduse))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ix
+}
+
+func at(lng, lat float64, city, state string) types.Location {
+	return types.Location{Coordinates: types.Coordinates{Lat: lat, Lng: lng}, City: city, State: state}
+}
+
+func TestCheck(t *testing.T) {
+	ix := testIndex(t)
+	tests := []struct {
+		name      string
+		loc       types.Location
+		want      Report
+		buildable bool
+	}{
+		{
+			name:      "match",
+			loc:       at(15, 15, "Springfield", "Alpha"),
+			want:      Report{Valid: true, State: "Alpha", City: "Springfield", StateCheck: MatchOK, CityCheck: MatchOK},
+			buildable: true,
+		},
+		{
+			name:      "hole is outside the city",
+			loc:       at(13.5, 13.5, "Springfield", "Alpha"),
+			want:      Report{Valid: true, State: "Alpha", StateCheck: MatchOK, CityCheck: MatchMismatch},
+			buildable: true,
+		},
+		{
+			name:      "aliases normalize",
+			loc:       at(18.5, 12, "harbour town", "al"),
+			want:      Report{Valid: true, State: "Alpha", City: "Port Town", StateCheck: MatchOK, CityCheck: MatchOK},
+			buildable: true,
+		},
+		{
+			name:      "outside the triangle's hypotenuse",
+			loc:       at(17, 13, "Port Town", "Alpha"),
+			want:      Report{Valid: true, State: "Alpha", StateCheck: MatchOK, CityCheck: MatchMismatch},
+			buildable: true,
+		},
+		{
+			name:      "same city name in the claimed state",
+			loc:       at(24, 14, "Springfield", "Beta"),
+			want:      Report{Valid: true, State: "Beta", City: "Springfield", StateCheck: MatchOK, CityCheck: MatchOK},
+			buildable: true,
+		},
+		{
+			name:      "same city name in another state",
+			loc:       at(24, 14, "Springfield", "AL"),
+			want:      Report{Valid: true, State: "Beta", City: "Springfield", StateCheck: MatchMismatch, CityCheck: MatchMismatch},
+			buildable: true,
+		},
+		{
+			name:      "city of an unknown state",
+			loc:       at(24, 14, "Springfield", "Gamma"),
+			want:      Report{Valid: true, State: "Beta", City: "Springfield", StateCheck: MatchUnknown, CityCheck: MatchOK},
+			buildable: true,
+		},
+		{
+			name:      "city without a state, second polygon",
+			loc:       at(27.5, 17.5, "Freeport", "Beta"),
+			want:      Report{Valid: true, State: "Beta", City: "Freeport", StateCheck: MatchOK, CityCheck: MatchOK},
+			buildable: true,
+		},
+		{
+			name:      "unknown city",
+			loc:       at(15, 15, "Gotham", "Alpha"),
+			want:      Report{Valid: true, State: "Alpha", City: "Springfield", StateCheck: MatchOK, CityCheck: MatchUnknown},
+			buildable: true,
+		},
+		{
+			name:      "nothing claimed",
+			loc:       at(15, 15, "", ""),
+			want:      Report{Valid: true, State: "Alpha", City: "Springfield", StateCheck: MatchUnknown, CityCheck: MatchUnknown},
+			buildable: true,
+		},
+		{
+			name:      "outside every boundary",
+			loc:       at(40, 40, "Springfield", "Alpha"),
+			want:      Report{Valid: true, StateCheck: MatchMismatch, CityCheck: MatchMismatch},
+			buildable: true,
+		},
+		{
+			name: "water",
+			loc:  at(28, 13, "", "Beta"),
+			want: Report{Valid: true, State: "Beta", StateCheck: MatchOK, CityCheck: MatchUnknown, Water: "Blue Lake"},
+		},
+		{
+			name: "non-buildable",
+			loc:  at(18, 18, "", "Alpha"),
+			want: Report{Valid: true, State: "Alpha", StateCheck: MatchOK, CityCheck: MatchUnknown, NonBuildable: "Old Forest (protected)"},
+		},
+		{
+			name: "unset coordinates",
+			loc:  at(0, 0, "Springfield", "Alpha"),
+			want: Report{Error: "coordinates 0,0 are unset", StateCheck: MatchUnknown, CityCheck: MatchUnknown},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := ix.Check(tc.loc)
+			if got != tc.want {
+				t.Errorf("got %+v\nwant %+v", got, tc.want)
+			}
+			if got.Buildable() != tc.buildable {
+				t.Errorf("buildable %v, want %v", got.Buildable(), tc.buildable)
+			}
+		})
+	}
+}
+
+func TestLoadRejectsBadData(t *testing.T) {
+	tests := []struct {
+		name       string
+		boundaries string
+		landuse    string
+		wantErr    string
+	}{
+		{"not a collection", `{"type": "Feature"}`, testLanduse, "must be a FeatureCollection"},
+		{
+			name:       "unknown level",
+			boundaries: `{"type": "FeatureCollection", "features": [{"properties": {"name": "X", "level": "county"}, "geometry": {"type": "Polygon", "coordinates": [[[1, 1], [2, 1], [2, 2], [1, 1]]]}}]}`,
+			landuse:    testLanduse,
+			wantErr:    `level "county"`,
+		},
+		{
+			name:       "open ring",
+			boundaries: `{"type": "FeatureCollection", "features": [{"properties": {"name": "X", "level": "state"}, "geometry": {"type": "Polygon", "coordinates": [[[1, 1], [2, 1], [1, 1]]]}}]}`,
+			landuse:    testLanduse,
+			wantErr:    "need at least 4",
+		},
+		{
+			name:       "point geometry",
+			boundaries: `{"type": "FeatureCollection", "features": [{"properties": {"name": "X", "level": "city"}, "geometry": {"type": "Point", "coordinates": [1, 1]}}]}`,
+			landuse:    testLanduse,
+			wantErr:    "not a Polygon",
+		},
+		{
+			name:       "unknown land use class",
+			boundaries: testBoundaries,
+			landuse:    `{"type": "FeatureCollection", "features": [{"properties": {"name": "X", "class": "farm"}, "geometry": {"type": "Polygon", "coordinates": [[[1, 1], [2, 1], [2, 2], [1, 1]]]}}]}`,
+			wantErr:    `class "farm"`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Load(strings.NewReader(tc.boundaries), strings.NewReader(tc.landuse))
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("got error %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestBundled(t *testing.T) {
+	ix, err := Bundled()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := ix.Check(at(72.88, 19.07, "Bombay", "MH"))
+	if r.StateCheck != MatchOK || r.CityCheck != MatchOK {
+		t.Errorf("Mumbai: %+v", r)
+	}
+}
@@ -0,0 +1,147 @@
+package geo
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// feature is a GeoJSON feature reduced to what the index needs
+type feature struct {
+	props    properties
+	polygons []polygon
+}
+
+// properties used from the bundled (or operator supplied) GeoJSON. Boundaries
+// set level, state (for cities) and aliases; land use sets class and reason.
+type properties struct {
+	Name    string   `json:"name"`
+	Level   string   `json:"level"`
+	State   string   `json:"state"`
+	Aliases []string `json:"aliases"`
+	Class   string   `json:"class"`
+	Reason  string   `json:"reason"`
+}
+
+// polygon is an outer ring and its holes, as [lng, lat] points
+type polygon struct {
+	rings                  [][][2]float64
+	minX, minY, maxX, maxY float64
+}
+
+// readFeatures parses a FeatureCollection of Polygon and MultiPolygon features
+func readFeatures(r io.Reader) ([]feature, error) {
+	var fc struct {
+		Type     string `json:"type"`
+		Features []struct {
+			Properties properties `json:"properties"`
+			Geometry   struct {
+				Type        string          `json:"type"`
+				Coordinates json.RawMessage `json:"coordinates"`
+			} `json:"geometry"`
+		} `json:"features"`
+	}
+	if err := json.NewDecoder(r).Decode(&fc); err != nil {
+		return nil, fmt.Errorf("invalid GeoJSON: %v", err)
+	}
+	if fc.Type != "FeatureCollection" {
+		return nil, fmt.Errorf("GeoJSON must be a FeatureCollection, got %q", fc.Type)
+	}
+
+	features := make([]feature, 0, len(fc.Features))
+	for i, f := range fc.Features {
+		if f.Properties.Name == "" {
+			return nil, fmt.Errorf("feature %d has no name", i)
+		}
+		var raw [][][][]float64
+		switch f.Geometry.Type {
+		case "Polygon":
+			var p [][][]float64
+			if err := json.Unmarshal(f.Geometry.Coordinates, &p); err != nil {
+				return nil, fmt.Errorf("feature %s: %v", f.Properties.Name, err)
+			}
+			raw = [][][][]float64{p}
+		case "MultiPolygon":
+			if err := json.Unmarshal(f.Geometry.Coordinates, &raw); err != nil {
+				return nil, fmt.Errorf("feature %s: %v", f.Properties.Name, err)
+			}
+		default:
+			return nil, fmt.Errorf("feature %s: geometry %q is not a Polygon or MultiPolygon", f.Properties.Name, f.Geometry.Type)
+		}
+
+		ft := feature{props: f.Properties}
+		for _, rings := range raw {
+			p, err := newPolygon(rings)
+			if err != nil {
+				return nil, fmt.Errorf("feature %s: %v", f.Properties.Name, err)
+			}
+			ft.polygons = append(ft.polygons, p)
+		}
+		features = append(features, ft)
+	}
+	return features, nil
+}
+
+func newPolygon(rings [][][]float64) (polygon, error) {
+	if len(rings) == 0 {
+		return polygon{}, fmt.Errorf("polygon has no rings")
+	}
+	p := polygon{minX: 180, minY: 90, maxX: -180, maxY: -90}
+	for _, ring := range rings {
+		if len(ring) < 4 {
+			return polygon{}, fmt.Errorf("ring has %d positions, need at least 4", len(ring))
+		}
+		pts := make([][2]float64, len(ring))
+		for i, pos := range ring {
+			if len(pos) < 2 {
+				return polygon{}, fmt.Errorf("position has %d coordinates", len(pos))
+			}
+			pts[i] = [2]float64{pos[0], pos[1]}
+		}
+		p.rings = append(p.rings, pts)
+	}
+	// The outer ring bounds the polygon
+	for _, pt := range p.rings[0] {
+		p.minX, p.maxX = min(p.minX, pt[0]), max(p.maxX, pt[0])
+		p.minY, p.maxY = min(p.minY, pt[1]), max(p.maxY, pt[1])
+	}
+	return p, nil
+}
+
+func (f feature) contains(lng, lat float64) bool {
+	for _, p := range f.polygons {
+		if p.contains(lng, lat) {
+			return true
+		}
+	}
+	return false
+}
+
+// contains reports whether the point is inside the outer ring and outside every hole
+func (p polygon) contains(lng, lat float64) bool {
+	if lng < p.minX || lng > p.maxX || lat < p.minY || lat > p.maxY {
+		return false
+	}
+	if !inRing(p.rings[0], lng, lat) {
+		return false
+	}
+	for _, hole := range p.rings[1:] {
+		if inRing(hole, lng, lat) {
+			return false
+		}
+	}
+	return true
+}
+
+// inRing is the even-odd ray casting test
+func inRing(ring [][2]float64, x, y float64) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		xi, yi := ring[i][0], ring[i][1]
+		xj, yj := ring[j][0], ring[j][1]
+		if (yi > y) != (yj > y) && x < (xj-xi)*(y-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+	return inside
+}
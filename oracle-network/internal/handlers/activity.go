@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/yourorg/proptoken-oracle/internal/config"
+	"github.com/yourorg/proptoken-oracle/pkg/types"
+)
+
+type ActivityVerifier struct {
+	Providers []WeightedProvider
+	Scoring   config.ScoringConfig
+}
+
+// NewActivityVerifier runs the given providers (e.g. utility usage, tax payment, occupancy)
+func NewActivityVerifier(providers []WeightedProvider, scoring config.ScoringConfig) *ActivityVerifier {
+	return &ActivityVerifier{Providers: providers, Scoring: scoring}
+}
+
+func (a *ActivityVerifier) Verify(ctx context.Context, sub *types.SubmissionData, env Env) (types.ActivityResult, error) {
+	signals, finalScore, err := collectSignals(ctx, sub, env, a.Providers, a.Scoring.FailurePolicy)
+	if err != nil {
+		return types.ActivityResult{Signals: signals}, fmt.Errorf("activity: %v", err)
+	}
+
+	return types.ActivityResult{
+		Score:   finalScore,
+		Signals: signals,
+		Passed:  finalScore >= a.Scoring.Thresholds.MinActivity,
+	}, nil
+}
@@ -1,88 +1,368 @@
 package handlers
 
 import (
+	"context"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/yourorg/proptoken-oracle/internal/blockchain"
 	"github.com/yourorg/proptoken-oracle/internal/crypto"
+	"github.com/yourorg/proptoken-oracle/internal/store"
 	"github.com/yourorg/proptoken-oracle/pkg/types"
 )
 
 type OracleAggregator struct {
 	Existence *ExistenceVerifier
 	Ownership *OwnershipVerifier
-	Signer    *crypto.Signer
+	Activity  *ActivityVerifier
+	Signer    crypto.Signer
 	Chain     *blockchain.Client
+	Tracker   *blockchain.Tracker
+	Store     *store.Store
+
+	// EIP-712 domain and validity period of signed attestations
+	Domain crypto.AttestationDomain
+	TTL    time.Duration
+
+	// Source of each run's observation time; replace to replay or pin runs
+	Clock func() time.Time
 }
 
-func NewOracleAggregator(exist *ExistenceVerifier, own *OwnershipVerifier, signer *crypto.Signer, chain *blockchain.Client) *OracleAggregator {
+func NewOracleAggregator(exist *ExistenceVerifier, own *OwnershipVerifier, activity *ActivityVerifier, signer crypto.Signer, chain *blockchain.Client, tracker *blockchain.Tracker, st *store.Store, domain crypto.AttestationDomain, ttl time.Duration) *OracleAggregator {
 	return &OracleAggregator{
 		Existence: exist,
 		Ownership: own,
+		Activity:  activity,
 		Signer:    signer,
 		Chain:     chain,
+		Tracker:   tracker,
+		Store:     st,
+		Domain:    domain,
+		TTL:       ttl,
+		Clock:     time.Now,
 	}
 }
 
-func (a *OracleAggregator) VerifySubmission(sub *types.SubmissionData) (*types.OracleResult, error) {
-	// 1. Run Verifications
-	existenceRes := a.Existence.Verify(sub)
-	ownershipRes := a.Ownership.Verify(sub)
+// VerifySubmission runs the full pipeline: verify, sign, push on-chain and persist
+func (a *OracleAggregator) VerifySubmission(ctx context.Context, sub *types.SubmissionData) (*types.OracleResult, error) {
+	result, err := a.Attest(ctx, sub)
+	if err != nil {
+		return nil, err
+	}
 
-	// Activity mocked as pass for now
-	activityRes := types.ActivityResult{
-		Score:  0.9,
-		Passed: true,
-		Signals: map[string]types.SignalData{
-			"foot_traffic": {Source: "MockGooglePlaces", Score: 0.9, Timestamp: time.Now()},
-		},
+	// Push failures are recorded on the stored verification, not returned
+	a.Anchor(ctx, sub, result)
+	return result, nil
+}
+
+// Attest verifies the submission with fresh run inputs and returns the signed
+// result (not yet on-chain)
+func (a *OracleAggregator) Attest(ctx context.Context, sub *types.SubmissionData) (*types.OracleResult, error) {
+	return a.AttestWith(ctx, sub, a.NewInputs())
+}
+
+// NewInputs reads the observation time for a new run
+func (a *OracleAggregator) NewInputs() types.RunInputs {
+	return NewInputs(a.Clock)
+}
+
+// AttestWith verifies the submission under recorded inputs. Given the same
+// provider responses, it reproduces the signals and Merkle root of the run that
+// recorded them; consensus peers use it to verify under the coordinator's inputs.
+func (a *OracleAggregator) AttestWith(ctx context.Context, sub *types.SubmissionData, inputs types.RunInputs) (*types.OracleResult, error) {
+	env, err := NewEnv(inputs)
+	if err != nil {
+		return nil, err
 	}
 
-	// 2. Generate Merkle Proof
-	// Collect all signal hashes
-	var leaves []string
+	// 1. Run Verifications (concurrently; each provider has its own deadline)
+	var (
+		existenceRes             types.ExistenceResult
+		ownershipRes             types.OwnershipResult
+		activityRes              types.ActivityResult
+		existErr, ownErr, actErr error
+		wg                       sync.WaitGroup
+	)
+	wg.Add(3)
+	go func() { defer wg.Done(); existenceRes, existErr = a.Existence.Verify(ctx, sub, env) }()
+	go func() { defer wg.Done(); ownershipRes, ownErr = a.Ownership.Verify(ctx, sub, env) }()
+	go func() { defer wg.Done(); activityRes, actErr = a.Activity.Verify(ctx, sub, env) }()
+	wg.Wait()
 
-	// Existence Signals
-	for k, v := range existenceRes.Signals {
-		leaves = append(leaves, fmt.Sprintf("existence:%s:%v", k, v.Score))
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("verification aborted: %v", err)
 	}
-	// Ownership Signals
-	for k, v := range ownershipRes.Signals {
-		leaves = append(leaves, fmt.Sprintf("ownership:%s:%v", k, v.Score))
+	// Only set under the "fail" policy
+	for _, err := range []error{existErr, ownErr, actErr} {
+		if err != nil {
+			return nil, fmt.Errorf("verification failed: %v", err)
+		}
 	}
 
-	merkleRoot := crypto.GenerateMerkleRoot(leaves)
+	// Keep what the providers saw, so auditors can check it against the leaves
+	for _, signals := range []map[string]types.SignalData{existenceRes.Signals, ownershipRes.Signals, activityRes.Signals} {
+		if err := a.storeEvidence(signals); err != nil {
+			return nil, err
+		}
+	}
 
-	// 3. Sign Attestation
-	signature, err := a.Signer.SignAttestation(merkleRoot, sub.ID)
+	// 2. Generate Merkle Tree
+	leaves, err := signalLeaves(existenceRes.Signals, ownershipRes.Signals, activityRes.Signals)
 	if err != nil {
 		return nil, err
 	}
+	data := leafData(leaves)
+	tree := crypto.NewMerkleTree(data)
+	merkleRoot := tree.Root.Hex()
 
-	// 4. Push to Blockchain (Fire & Forget for demo, or blocking)
-	txHash := ""
-	if a.Chain != nil {
-		hash, err := a.Chain.PushAttestation(sub.ID, types.AttestationData{MerkleRoot: merkleRoot}, sub.IsMock)
-		if err == nil {
-			txHash = hash
-			fmt.Printf("Bitcoined Attestation: %s\n", txHash)
-		} else {
-			fmt.Printf("Blockchain Push Failed: %v\n", err)
+	// Inclusion proof per signal so each can be verified on its own
+	proofs := make(map[string]types.MerkleProof)
+	for key, leaf := range leaves {
+		proof, _ := tree.Proof(leaf)
+		proofs[key] = types.MerkleProof{
+			Leaf:     hexutil.Encode([]byte(leaf)),
+			LeafHash: crypto.HashLeaf(leaf).Hex(),
+			Proof:    crypto.EncodeProof(proof),
 		}
 	}
 
-	return &types.OracleResult{
+	result := &types.OracleResult{
 		SubmissionID: sub.ID,
 		Existence:    existenceRes,
 		Ownership:    ownershipRes,
 		Activity:     activityRes,
 		Attestation: types.AttestationData{
-			MerkleRoot:    merkleRoot,
-			OracleAddress: "0xMockOracleAddress",
-			Signature:     signature,
-			Timestamp:     time.Now(),
+			MerkleRoot: merkleRoot,
+			Proofs:     proofs,
 		},
-		Timestamp: time.Now(),
-	}, nil
+		Inputs:    inputs,
+		Timestamp: inputs.ObservedAt, // part of the replayable run, unlike the wall clock
+	}
+
+	// 3. Sign Attestation
+	if err := a.Sign(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// storeEvidence writes each signal's evidence to the content-addressed store
+func (a *OracleAggregator) storeEvidence(signals map[string]types.SignalData) error {
+	if a.Store == nil {
+		return nil
+	}
+	for name, signal := range signals {
+		if len(signal.Evidence) == 0 {
+			continue
+		}
+		if _, err := a.Store.PutEvidence(signal.Evidence); err != nil {
+			return fmt.Errorf("failed to store evidence for %s: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// Sign signs result's Merkle root and scores as EIP-712 typed data with this
+// node's key, filling in the signer, domain, validity period and digest
+func (a *OracleAggregator) Sign(result *types.OracleResult) error {
+	// The claim carries whole seconds, so store those
+	issued := result.Timestamp.Truncate(time.Second)
+	att := &result.Attestation
+	att.OracleAddress = a.Signer.Address().Hex()
+	att.Scheme = crypto.AttestationScheme
+	att.Domain = types.SigningDomain{
+		Name:              a.Domain.Name,
+		Version:           a.Domain.Version,
+		ChainID:           a.Domain.ChainID.String(),
+		VerifyingContract: a.Domain.VerifyingContract.Hex(),
+	}
+	att.Timestamp = issued
+	att.Expiry = issued.Add(a.TTL)
+
+	claim := attestationClaim(result, att.MerkleRoot)
+	digest, err := crypto.AttestationDigest(a.Domain, claim)
+	if err != nil {
+		return err
+	}
+	signature, err := crypto.SignAttestation(a.Signer, a.Domain, claim)
+	if err != nil {
+		return err
+	}
+	att.Digest = digest.Hex()
+	att.Signature = signature
+	return nil
+}
+
+// RecoverSigner checks that result's Merkle root matches its signals and returns
+// the address that signed it under this node's domain
+func (a *OracleAggregator) RecoverSigner(result *types.OracleResult) (common.Address, error) {
+	root, err := ResultRoot(result)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if !strings.EqualFold(root, result.Attestation.MerkleRoot) {
+		return common.Address{}, fmt.Errorf("merkle root %s does not match signals (%s)", result.Attestation.MerkleRoot, root)
+	}
+	return crypto.RecoverAttestationSigner(a.Domain, attestationClaim(result, root), result.Attestation.Signature)
+}
+
+// ResultRoot recomputes the Merkle root from result's signals
+func ResultRoot(result *types.OracleResult) (string, error) {
+	leaves, err := signalLeaves(result.Existence.Signals, result.Ownership.Signals, result.Activity.Signals)
+	if err != nil {
+		return "", err
+	}
+	return crypto.NewMerkleTree(leafData(leaves)).Root.Hex(), nil
+}
+
+// attestationClaim is the typed struct signed for result over merkleRoot. The
+// attestation verifier rebuilds it from a stored record, so it must only use
+// fields that are persisted.
+func attestationClaim(result *types.OracleResult, merkleRoot string) crypto.AttestationClaim {
+	return crypto.AttestationClaim{
+		Fingerprint:    blockchain.Fingerprint(result.SubmissionID),
+		MerkleRoot:     common.HexToHash(merkleRoot),
+		ExistenceScore: blockchain.ToFixed18(result.Existence.Score),
+		OwnershipScore: blockchain.ToFixed18(result.Ownership.Score),
+		ActivityScore:  blockchain.ToFixed18(result.Activity.Score),
+		Eligible:       result.Existence.Passed && result.Ownership.Passed && result.Activity.Passed,
+		Timestamp:      uint64(result.Attestation.Timestamp.Unix()),
+		Expiry:         uint64(result.Attestation.Expiry.Unix()),
+	}
+}
+
+// leafArgs is the canonical leaf layout, matching Solidity's
+// abi.encode(category, signal, source, score, evidenceHash, observedAt)
+var leafArgs = func() abi.Arguments {
+	str, _ := abi.NewType("string", "", nil)
+	u256, _ := abi.NewType("uint256", "", nil)
+	b32, _ := abi.NewType("bytes32", "", nil)
+	u64, _ := abi.NewType("uint64", "", nil)
+	return abi.Arguments{{Type: str}, {Type: str}, {Type: str}, {Type: u256}, {Type: b32}, {Type: u64}}
+}()
+
+// signalLeaves turns each signal into one ABI-encoded leaf, keyed by
+// "<category>:<signal>". Scores are 1e18 fixed point and times unix seconds, so
+// the encoding depends only on the recorded signal values.
+func signalLeaves(existence, ownership, activity map[string]types.SignalData) (map[string]string, error) {
+	leaves := make(map[string]string)
+	for category, signals := range map[string]map[string]types.SignalData{
+		"existence": existence,
+		"ownership": ownership,
+		"activity":  activity,
+	} {
+		for name, signal := range signals {
+			leaf, err := encodeLeaf(category, name, signal)
+			if err != nil {
+				return nil, err
+			}
+			leaves[category+":"+name] = string(leaf)
+		}
+	}
+	return leaves, nil
+}
+
+func encodeLeaf(category, name string, signal types.SignalData) ([]byte, error) {
+	encoded, err := leafArgs.Pack(
+		category,
+		name,
+		signal.Source,
+		blockchain.ToFixed18(signal.Score),
+		common.HexToHash(signal.EvidenceHash),
+		uint64(signal.Timestamp.Unix()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s:%s leaf: %v", category, name, err)
+	}
+	return encoded, nil
+}
+
+func leafData(leaves map[string]string) []string {
+	var data []string
+	for _, leaf := range leaves {
+		data = append(data, leaf)
+	}
+	return data
+}
+
+// Anchor pushes a signed result to the registry (if a chain is configured) and
+// persists it, recording the tx on result. It returns the tx hash, or the push
+// error. The transaction is then tracked in the background and its final status
+// written to the stored record.
+func (a *OracleAggregator) Anchor(ctx context.Context, sub *types.SubmissionData, result *types.OracleResult) (string, error) {
+	// 4. Push to Blockchain
+	var (
+		tx      *ethtypes.Transaction
+		pushErr error
+	)
+	txHash, txStatus := "", ""
+	if a.Chain != nil {
+		tx, pushErr = a.Chain.PushAttestation(ctx, sub, result)
+		if pushErr == nil {
+			txHash, txStatus = tx.Hash().Hex(), types.TxPending
+			fmt.Printf("Bitcoined Attestation: %s\n", txHash)
+		} else {
+			txStatus = types.TxFailed
+			fmt.Printf("Blockchain Push Failed: %v\n", pushErr)
+		}
+		result.Attestation.TxHash, result.Attestation.TxStatus = txHash, txStatus
+	}
+
+	// 5. Persist so the result can be re-fetched later
+	a.save(sub, result, tx, pushErr)
+	return txHash, pushErr
+}
+
+// Record persists a signed result without pushing it, for nodes that are not
+// the registry submitter
+func (a *OracleAggregator) Record(sub *types.SubmissionData, result *types.OracleResult) {
+	a.save(sub, result, nil, nil)
+}
+
+func (a *OracleAggregator) save(sub *types.SubmissionData, result *types.OracleResult, tx *ethtypes.Transaction, pushErr error) {
+	if a.Store != nil {
+		status := types.VerificationFailed
+		if result.Existence.Passed && result.Ownership.Passed && result.Activity.Passed {
+			status = types.VerificationPassed
+		}
+		rec := &types.VerificationRecord{
+			SubmissionID: sub.ID,
+			Status:       status,
+			Result:       *result,
+			TxHash:       result.Attestation.TxHash,
+			TxStatus:     result.Attestation.TxStatus,
+			CreatedAt:    result.Timestamp,
+		}
+		if pushErr != nil {
+			rec.TxError = pushErr.Error()
+		}
+		if err := a.Store.SaveVerification(rec); err != nil {
+			fmt.Printf("Failed to persist verification %s: %v\n", sub.ID, err)
+		} else if tx != nil && a.Tracker != nil {
+			// Outlives the request; Tracker gives up after chain.drop_after
+			go a.track(sub.ID, rec.Version, tx)
+		}
+	}
+}
+
+// track waits for tx to become final and records the outcome against the stored version
+func (a *OracleAggregator) track(submissionID string, version int, tx *ethtypes.Transaction) {
+	outcome := a.Tracker.Track(context.Background(), tx)
+	fmt.Printf("Attestation %s for %s: %s %s\n", outcome.Hash, submissionID, outcome.Status, outcome.RevertReason)
+
+	update := store.TxUpdate{
+		Hash:   outcome.Hash,
+		Status: outcome.Status,
+		Block:  outcome.BlockNumber,
+		Error:  outcome.RevertReason,
+	}
+	if err := a.Store.UpdateTxStatus(submissionID, version, update); err != nil {
+		fmt.Printf("Failed to record tx status for %s: %v\n", submissionID, err)
+	}
 }
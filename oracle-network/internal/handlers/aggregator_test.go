@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/yourorg/proptoken-oracle/internal/config"
+	"github.com/yourorg/proptoken-oracle/internal/crypto"
+	"github.com/yourorg/proptoken-oracle/internal/store"
+	"github.com/yourorg/proptoken-oracle/pkg/types"
+)
+
+// recordedProvider replays a fixed provider response
+type recordedProvider struct {
+	name     string
+	score    float64
+	evidence []byte
+}
+
+func (p recordedProvider) Name() string { return p.name }
+
+func (p recordedProvider) Fetch(ctx context.Context, sub *types.SubmissionData, env Env) (types.SignalData, error) {
+	return types.SignalData{
+		Source:    "recorded",
+		Score:     p.score,
+		Data:      map[string]interface{}{"bytes": len(p.evidence)},
+		Evidence:  p.evidence,
+		Timestamp: env.Now(),
+	}, nil
+}
+
+func newTestAggregator(t *testing.T, evidence []byte) (*OracleAggregator, *store.Store) {
+	t.Helper()
+	signer, err := crypto.NewKeySigner("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	if err != nil {
+		t.Fatal(err)
+	}
+	st, err := store.Open(filepath.Join(t.TempDir(), "oracle.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { st.Close() })
+
+	providers := func(names ...string) []WeightedProvider {
+		var wps []WeightedProvider
+		for _, name := range names {
+			wps = append(wps, WeightedProvider{
+				Key:      name,
+				Provider: recordedProvider{name: name, score: 0.9, evidence: evidence},
+				Weight:   1,
+				Timeout:  time.Second,
+			})
+		}
+		return wps
+	}
+	scoring := config.ScoringConfig{FailurePolicy: config.PolicyExclude}
+	domain := crypto.AttestationDomain{Name: "PropToken Oracle", Version: "1", ChainID: big.NewInt(84532), VerifyingContract: common.HexToAddress("0x1")}
+
+	a := NewOracleAggregator(
+		NewExistenceVerifier(providers("satellite_imagery", "geo_validation"), scoring),
+		NewOwnershipVerifier(providers("deed"), scoring),
+		NewActivityVerifier(providers("utility"), scoring),
+		signer, nil, nil, st, domain, time.Hour,
+	)
+	// A wall clock that moves on every read; the result must not depend on it
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	a.Clock = func() time.Time {
+		now = now.Add(time.Minute)
+		return now
+	}
+	return a, st
+}
+
+func TestAttestWithReplaysRecordedRun(t *testing.T) {
+	evidence := []byte(`{"tile":"z18/1/2","pixels":"..."}`)
+	inputs := types.RunInputs{ObservedAt: time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)}
+	sub := &types.SubmissionData{ID: "sub-1"}
+
+	a, st := newTestAggregator(t, evidence)
+	first, err := a.AttestWith(context.Background(), sub, inputs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayed, err := a.AttestWith(context.Background(), sub, inputs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if first.Attestation.MerkleRoot != replayed.Attestation.MerkleRoot {
+		t.Errorf("replay root %s, want %s", replayed.Attestation.MerkleRoot, first.Attestation.MerkleRoot)
+	}
+	if first.Attestation.Signature != replayed.Attestation.Signature {
+		t.Error("replay signature differs")
+	}
+	if !first.Timestamp.Equal(inputs.ObservedAt) || !first.Attestation.Timestamp.Equal(inputs.ObservedAt) {
+		t.Errorf("timestamp %s / %s, want observed time %s", first.Timestamp, first.Attestation.Timestamp, inputs.ObservedAt)
+	}
+
+	root, err := ResultRoot(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root != first.Attestation.MerkleRoot {
+		t.Errorf("recomputed root %s, want %s", root, first.Attestation.MerkleRoot)
+	}
+	signer, err := a.RecoverSigner(first)
+	if err != nil || signer != a.Signer.Address() {
+		t.Errorf("recovered signer %s (%v), want %s", signer.Hex(), err, a.Signer.Address().Hex())
+	}
+
+	hash := first.Existence.Signals["satellite_imagery"].EvidenceHash
+	stored, err := st.GetEvidence(hash)
+	if err != nil || string(stored) != string(evidence) {
+		t.Errorf("evidence %s = %q (%v), want the provider response", hash, stored, err)
+	}
+
+	// A different observation time or different evidence is a different run
+	later := inputs
+	later.ObservedAt = inputs.ObservedAt.Add(time.Second)
+	moved, err := a.AttestWith(context.Background(), sub, later)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if moved.Attestation.MerkleRoot == first.Attestation.MerkleRoot {
+		t.Error("root unchanged for a different observation time")
+	}
+
+	other, _ := newTestAggregator(t, []byte(`{"tile":"z18/1/3"}`))
+	changed, err := other.AttestWith(context.Background(), sub, inputs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed.Attestation.MerkleRoot == first.Attestation.MerkleRoot {
+		t.Error("root unchanged for different evidence")
+	}
+}
+
+func TestAttestWithRejectsUnsetTime(t *testing.T) {
+	a, _ := newTestAggregator(t, nil)
+	_, err := a.AttestWith(context.Background(), &types.SubmissionData{ID: "sub-1"}, types.RunInputs{})
+	if err == nil {
+		t.Error("accepted inputs without an observation time")
+	}
+}
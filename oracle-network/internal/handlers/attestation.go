@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/yourorg/proptoken-oracle/internal/blockchain"
+	"github.com/yourorg/proptoken-oracle/internal/crypto"
+	"github.com/yourorg/proptoken-oracle/internal/store"
+	"github.com/yourorg/proptoken-oracle/pkg/types"
+)
+
+// AttestationVerifier checks a registry entry against the stored evidence:
+// the Merkle root recomputed from the stored signals must equal the on-chain
+// oracleAttestation, and the stored EIP-712 signature must come from an ORACLE_ROLE holder.
+type AttestationVerifier struct {
+	Chain  *blockchain.Client
+	Store  *store.Store
+	Domain crypto.AttestationDomain
+}
+
+func NewAttestationVerifier(chain *blockchain.Client, st *store.Store, domain crypto.AttestationDomain) *AttestationVerifier {
+	return &AttestationVerifier{Chain: chain, Store: st, Domain: domain}
+}
+
+// Verify accepts a 0x fingerprint or the submission ID it was derived from.
+// Mismatches are reported in the result; the error is for lookups that failed outright.
+func (v *AttestationVerifier) Verify(ctx context.Context, id string) (*types.AttestationCheck, error) {
+	fingerprint := blockchain.Fingerprint(id)
+	check := &types.AttestationCheck{Fingerprint: common.Hash(fingerprint).Hex()}
+
+	opts := &bind.CallOpts{Context: ctx}
+	asset, err := v.Chain.Registry.GetAsset(opts, fingerprint)
+	if err != nil {
+		if strings.Contains(err.Error(), "Asset not registered") {
+			return nil, store.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to read registry: %v", err)
+	}
+	check.OnChainRoot = common.Hash(asset.OracleAttestation).Hex()
+
+	history, err := v.history(id, check.Fingerprint)
+	if errors.Is(err, store.ErrNotFound) {
+		check.Error = "no stored verification for this fingerprint"
+		return check, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	// The version that was anchored, or the latest if none matches
+	rec := history[len(history)-1]
+	for i := len(history) - 1; i >= 0; i-- {
+		if strings.EqualFold(history[i].Result.Attestation.MerkleRoot, check.OnChainRoot) {
+			rec = history[i]
+			break
+		}
+	}
+	check.SubmissionID = rec.SubmissionID
+	check.Version = rec.Version
+
+	// Recompute from the signals rather than trusting the stored root
+	res := rec.Result
+	check.RecomputedRoot, err = ResultRoot(&res)
+	if err != nil {
+		check.Error = err.Error()
+		return check, nil
+	}
+	check.RootMatches = check.RecomputedRoot == check.OnChainRoot
+
+	claim := attestationClaim(&res, check.RecomputedRoot)
+	signer, err := crypto.RecoverAttestationSigner(v.Domain, claim, res.Attestation.Signature)
+	if err != nil {
+		check.Error = err.Error()
+		return check, nil
+	}
+	check.Signer = signer.Hex()
+
+	role, err := v.Chain.Registry.ORACLEROLE(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ORACLE_ROLE: %v", err)
+	}
+	check.SignerHasRole, err = v.Chain.Registry.HasRole(opts, role, signer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check role: %v", err)
+	}
+
+	check.Expired = time.Now().After(res.Attestation.Expiry)
+	check.Valid = check.RootMatches && check.SignerHasRole && !check.Expired
+	return check, nil
+}
+
+// history finds the stored versions for a fingerprint, looking the submission
+// up by ID first and then by scanning for the ID that hashes to it
+func (v *AttestationVerifier) history(id, fingerprint string) ([]types.VerificationRecord, error) {
+	if history, err := v.Store.History(id); !errors.Is(err, store.ErrNotFound) {
+		return history, err
+	}
+
+	ids, err := v.Store.SubmissionIDs()
+	if err != nil {
+		return nil, err
+	}
+	for _, subID := range ids {
+		if common.Hash(blockchain.Fingerprint(subID)).Hex() == fingerprint {
+			return v.Store.History(subID)
+		}
+	}
+	return nil, store.ErrNotFound
+}
@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/yourorg/proptoken-oracle/internal/blockchain"
+	"github.com/yourorg/proptoken-oracle/internal/config"
+	"github.com/yourorg/proptoken-oracle/internal/crypto"
+	"github.com/yourorg/proptoken-oracle/internal/store"
+	"github.com/yourorg/proptoken-oracle/pkg/types"
+)
+
+// newAnchoringAggregator is newTestAggregator bound to a registry on a simulated
+// chain, where its signer holds CONSENSUS_ROLE and ORACLE_ROLE
+func newAnchoringAggregator(t *testing.T) (*OracleAggregator, *AttestationVerifier, *simulated.Backend) {
+	t.Helper()
+	a, st := newTestAggregator(t, []byte(`{"tile":"z18/1/2"}`))
+	a.Clock = time.Now
+
+	sim := simulated.NewBackend(ethtypes.GenesisAlloc{
+		a.Signer.Address(): {Balance: new(big.Int).Mul(big.NewInt(100), big.NewInt(params.Ether))},
+	})
+	t.Cleanup(func() { sim.Close() })
+	backend := sim.Client()
+	chainID, _ := backend.ChainID(context.Background())
+	auth, err := bind.NewKeyedTransactorWithChainID(ethcrypto.ToECDSAUnsafe(common.FromHex("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")), chainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, _, registry, err := blockchain.DeployAssetRegistry(auth, backend, a.Signer.Address())
+	if err != nil {
+		t.Fatalf("deploy: %v", err)
+	}
+	sim.Commit()
+	for _, name := range []string{"CONSENSUS_ROLE", "ORACLE_ROLE"} {
+		if _, err := registry.GrantRole(auth, ethcrypto.Keccak256Hash([]byte(name)), a.Signer.Address()); err != nil {
+			t.Fatalf("grant %s: %v", name, err)
+		}
+		sim.Commit()
+	}
+
+	a.Chain, err = blockchain.NewClientWithBackend(backend, config.ChainConfig{RegistryAddress: addr.Hex()}, a.Signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(a.Chain.Close)
+	return a, NewAttestationVerifier(a.Chain, st, a.Domain), sim
+}
+
+// anchor attests sub and mines its registration
+func anchor(t *testing.T, a *OracleAggregator, sim *simulated.Backend, sub *types.SubmissionData) *types.OracleResult {
+	t.Helper()
+	result, err := a.Attest(context.Background(), sub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.Anchor(context.Background(), sub, result); err != nil {
+		t.Fatalf("anchor: %v", err)
+	}
+	sim.Commit()
+	return result
+}
+
+func TestVerifyAttestation(t *testing.T) {
+	a, v, sim := newAnchoringAggregator(t)
+	sub := &types.SubmissionData{ID: "sub-1", Owner: "0x00000000000000000000000000000000000000AA"}
+	result := anchor(t, a, sim, sub)
+
+	for _, id := range []string{sub.ID, common.Hash(blockchain.Fingerprint(sub.ID)).Hex()} {
+		check, err := v.Verify(context.Background(), id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !check.Valid || !check.RootMatches || !check.SignerHasRole || check.Expired || check.Error != "" {
+			t.Errorf("check by %s: %+v, want valid", id, check)
+		}
+		if check.SubmissionID != sub.ID || check.Version != 1 {
+			t.Errorf("check by %s used %s v%d", id, check.SubmissionID, check.Version)
+		}
+		if check.OnChainRoot != result.Attestation.MerkleRoot || check.Signer != a.Signer.Address().Hex() {
+			t.Errorf("check by %s: root %s signed by %s", id, check.OnChainRoot, check.Signer)
+		}
+	}
+}
+
+func TestVerifyAttestationRejectsTampering(t *testing.T) {
+	other, err := crypto.NewKeySigner("8a1f9a8f95be41cd7ccb6168179afb4504aefe388d1e14474d32c45c72ce7b7a")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		tamper func(a *OracleAggregator, result *types.OracleResult)
+		check  func(t *testing.T, check *types.AttestationCheck)
+	}{
+		{
+			name: "leaf",
+			tamper: func(a *OracleAggregator, result *types.OracleResult) {
+				// Same claimed root, but one signal no longer hashes to it
+				signals := make(map[string]types.SignalData)
+				for k, s := range result.Existence.Signals {
+					signals[k] = s
+				}
+				s := signals["satellite_imagery"]
+				s.Score = 1
+				signals["satellite_imagery"] = s
+				result.Existence.Signals = signals
+			},
+			check: func(t *testing.T, check *types.AttestationCheck) {
+				if check.RootMatches || check.RecomputedRoot == check.OnChainRoot {
+					t.Errorf("recomputed root %s matches on-chain %s", check.RecomputedRoot, check.OnChainRoot)
+				}
+			},
+		},
+		{
+			name: "wrong signer",
+			tamper: func(a *OracleAggregator, result *types.OracleResult) {
+				forger := &OracleAggregator{Signer: other, Domain: a.Domain, TTL: a.TTL}
+				if err := forger.Sign(result); err != nil {
+					t.Fatal(err)
+				}
+			},
+			check: func(t *testing.T, check *types.AttestationCheck) {
+				if !check.RootMatches || check.Signer != other.Address().Hex() || check.SignerHasRole {
+					t.Errorf("check %+v, want the root to match but %s without ORACLE_ROLE", check, other.Address().Hex())
+				}
+			},
+		},
+		{
+			name: "signature",
+			tamper: func(a *OracleAggregator, result *types.OracleResult) {
+				result.Attestation.Signature = "0x1234"
+			},
+			check: func(t *testing.T, check *types.AttestationCheck) {
+				if check.Error == "" || check.Signer != "" {
+					t.Errorf("check %+v, want an unrecoverable signature", check)
+				}
+			},
+		},
+		{
+			name: "expired",
+			tamper: func(a *OracleAggregator, result *types.OracleResult) {
+				result.Timestamp = time.Now().Add(-2 * a.TTL)
+				if err := a.Sign(result); err != nil {
+					t.Fatal(err)
+				}
+			},
+			check: func(t *testing.T, check *types.AttestationCheck) {
+				if !check.RootMatches || !check.SignerHasRole || !check.Expired {
+					t.Errorf("check %+v, want only expired", check)
+				}
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			a, v, sim := newAnchoringAggregator(t)
+			sub := &types.SubmissionData{ID: "sub-1", Owner: "0x00000000000000000000000000000000000000AA"}
+			result := anchor(t, a, sim, sub)
+
+			// Stored as a newer version under the anchored root
+			tampered := *result
+			tc.tamper(a, &tampered)
+			a.Record(sub, &tampered)
+
+			check, err := v.Verify(context.Background(), sub.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if check.Version != 2 {
+				t.Errorf("checked version %d, want the tampered one", check.Version)
+			}
+			if check.Valid {
+				t.Errorf("tampered attestation passed: %+v", check)
+			}
+			tc.check(t, check)
+		})
+	}
+}
+
+func TestVerifyAttestationNotFound(t *testing.T) {
+	a, v, sim := newAnchoringAggregator(t)
+	if _, err := v.Verify(context.Background(), "sub-unknown"); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("unregistered asset: got %v, want ErrNotFound", err)
+	}
+
+	// Registered on chain, but this node has no record of it
+	sub := &types.SubmissionData{ID: "sub-1", Owner: "0x00000000000000000000000000000000000000AA"}
+	anchor(t, a, sim, sub)
+	ids, err := v.Store.SubmissionIDs()
+	if err != nil || len(ids) != 1 {
+		t.Fatalf("stored %v (%v)", ids, err)
+	}
+	v.Store = openEmptyStore(t)
+
+	check, err := v.Verify(context.Background(), sub.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if check.Valid || check.Error != "no stored verification for this fingerprint" {
+		t.Errorf("check %+v, want no stored verification", check)
+	}
+}
+
+func openEmptyStore(t *testing.T) *store.Store {
+	t.Helper()
+	st, err := store.Open(filepath.Join(t.TempDir(), "empty.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { st.Close() })
+	return st
+}
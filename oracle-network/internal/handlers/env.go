@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yourorg/proptoken-oracle/pkg/types"
+)
+
+// Env is the clock a verification run hands its providers. Providers must take
+// time only from here, never from time.Now, so the run can be replayed from its
+// recorded inputs.
+type Env struct {
+	inputs types.RunInputs
+}
+
+// NewInputs reads a fresh observation time from clock
+func NewInputs(clock func() time.Time) types.RunInputs {
+	return types.RunInputs{ObservedAt: clock().UTC().Truncate(time.Second)}
+}
+
+// NewEnv replays inputs
+func NewEnv(inputs types.RunInputs) (Env, error) {
+	if inputs.ObservedAt.IsZero() {
+		return Env{}, fmt.Errorf("run inputs have no observation time")
+	}
+	return Env{inputs: inputs}, nil
+}
+
+// Now is the run's observation time; it does not advance during the run
+func (e Env) Now() time.Time {
+	return e.inputs.ObservedAt
+}
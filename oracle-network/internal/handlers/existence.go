@@ -1,51 +1,33 @@
 package handlers
 
 import (
-    "time"
-    "github.com/yourorg/proptoken-oracle/internal/integrations"
+    "context"
+    "fmt"
+    "github.com/yourorg/proptoken-oracle/internal/config"
     "github.com/yourorg/proptoken-oracle/pkg/types"
 )
 
 type ExistenceVerifier struct {
-    Satellite *integrations.SatelliteClient
-    Vision    *integrations.VisionClient
+    Providers []WeightedProvider
+    Scoring   config.ScoringConfig
 }
 
-func NewExistenceVerifier(sat *integrations.SatelliteClient, vis *integrations.VisionClient) *ExistenceVerifier {
-    return &ExistenceVerifier{Satellite: sat, Vision: vis}
+// NewExistenceVerifier runs the given providers (e.g. satellite imagery, vision analysis, geo validation)
+func NewExistenceVerifier(providers []WeightedProvider, scoring config.ScoringConfig) *ExistenceVerifier {
+    return &ExistenceVerifier{Providers: providers, Scoring: scoring}
 }
 
-func (e *ExistenceVerifier) Verify(sub *types.SubmissionData) types.ExistenceResult {
-    signals := make(map[string]types.SignalData)
-    
-    // 1. Satellite Imagery
-    imageURL, _ := e.Satellite.GetSatelliteImage(sub.Location.Coordinates)
-    signals["satellite_image"] = types.SignalData{
-        Source: "OpenStreetMap/Yandex",
-        Score: 1.0, // Image fetched successfully
-        Data: imageURL,
-        Timestamp: time.Now(),
-    }
-    
-    // 2. Vision Analysis (Mocked)
-    visionScore, _ := e.Vision.AnalyzeImage(imageURL)
-    signals["vision_analysis"] = types.SignalData{
-        Source: "ComputerVision",
-        Score: visionScore,
-        Data: "Building detected with high confidence",
-        Timestamp: time.Now(),
+func (e *ExistenceVerifier) Verify(ctx context.Context, sub *types.SubmissionData, env Env) (types.ExistenceResult, error) {
+    // Aggregate (weighted average of configured signal weights)
+    signals, finalScore, err := collectSignals(ctx, sub, env, e.Providers, e.Scoring.FailurePolicy)
+    if err != nil {
+        return types.ExistenceResult{Signals: signals}, fmt.Errorf("existence: %v", err)
     }
     
-    // 3. Coordinate Cross-Check (Simple box check)
-    // In real app, check against valid_assets.json database
-    
-    // Aggregate
-    finalScore := (visionScore * 0.7) + 0.3 // Weight vision heavily
-    
     return types.ExistenceResult{
         Score: finalScore,
-        Confidence: 0.95,
+        Confidence: signalCoverage(e.Providers, signals),
         Signals: signals,
-        Passed: finalScore > 0.8,
-    }
+        Passed: finalScore >= e.Scoring.Thresholds.MinExistence,
+    }, nil
 }
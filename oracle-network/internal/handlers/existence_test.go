@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/yourorg/proptoken-oracle/internal/config"
+	"github.com/yourorg/proptoken-oracle/pkg/types"
+)
+
+// failingProvider is an integration that is down
+type failingProvider struct{ name string }
+
+func (p failingProvider) Name() string { return p.name }
+
+func (p failingProvider) Fetch(ctx context.Context, sub *types.SubmissionData, env Env) (types.SignalData, error) {
+	return types.SignalData{Source: "down", Timestamp: env.Now()}, errors.New("connection refused")
+}
+
+func TestExistenceConfidence(t *testing.T) {
+	env, err := NewEnv(types.RunInputs{ObservedAt: time.Unix(1700000000, 0).UTC()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	up := func(name string, weight float64) WeightedProvider {
+		return WeightedProvider{Key: name, Provider: recordedProvider{name: name, score: 0.8}, Weight: weight, Timeout: time.Second}
+	}
+	down := func(name string, weight float64) WeightedProvider {
+		return WeightedProvider{Key: name, Provider: failingProvider{name: name}, Weight: weight, Timeout: time.Second}
+	}
+
+	tests := []struct {
+		name           string
+		providers      []WeightedProvider
+		policy         string
+		wantScore      float64
+		wantConfidence float64
+		wantPassed     bool
+	}{
+		{"all answer", []WeightedProvider{up("satellite", 0.3), up("geo", 0.2)}, config.PolicyZero, 0.8, 1, true},
+		{"one down, zeroed", []WeightedProvider{up("satellite", 0.3), down("geo", 0.1)}, config.PolicyZero, 0.6, 0.75, false},
+		{"one down, excluded", []WeightedProvider{up("satellite", 0.3), down("geo", 0.1)}, config.PolicyExclude, 0.8, 0.75, true},
+		{"all down", []WeightedProvider{down("satellite", 0.3), down("geo", 0.2)}, config.PolicyExclude, 0, 0, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// A score exactly at the minimum passes
+			scoring := config.ScoringConfig{FailurePolicy: tc.policy, Thresholds: config.Thresholds{MinExistence: 0.8}}
+			res, err := NewExistenceVerifier(tc.providers, scoring).Verify(context.Background(), &types.SubmissionData{}, env)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(res.Score-tc.wantScore) > 1e-9 || math.Abs(res.Confidence-tc.wantConfidence) > 1e-9 || res.Passed != tc.wantPassed {
+				t.Errorf("score %v confidence %v passed %v, want %v %v %v", res.Score, res.Confidence, res.Passed, tc.wantScore, tc.wantConfidence, tc.wantPassed)
+			}
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/yourorg/proptoken-oracle/internal/config"
+	"github.com/yourorg/proptoken-oracle/pkg/types"
+)
+
+// SignalProvider produces a single named signal for a submission.
+// Name is the key the signal is recorded under in the result and Merkle leaves.
+// Fetch must return promptly once ctx is done, and take the time from env.
+type SignalProvider interface {
+	Name() string
+	Fetch(ctx context.Context, sub *types.SubmissionData, env Env) (types.SignalData, error)
+}
+
+// WeightedProvider is a provider with its scoring weight and deadline inside a verifier
+type WeightedProvider struct {
+	Key      string
+	Provider SignalProvider
+	Weight   float64
+	Timeout  time.Duration
+}
+
+// ProviderRegistry holds the available providers under config keys
+// (e.g. "satellite", "registry"), so verifiers can be assembled from config.
+type ProviderRegistry struct {
+	mu        sync.RWMutex
+	providers map[string]SignalProvider
+}
+
+func NewProviderRegistry() *ProviderRegistry {
+	return &ProviderRegistry{providers: make(map[string]SignalProvider)}
+}
+
+// Register adds a provider under key. Keys must be unique.
+func (r *ProviderRegistry) Register(key string, p SignalProvider) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.providers[key]; exists {
+		return fmt.Errorf("provider %q already registered", key)
+	}
+	r.providers[key] = p
+	return nil
+}
+
+// Get returns the provider registered under key
+func (r *ProviderRegistry) Get(key string) (SignalProvider, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	p, ok := r.providers[key]
+	return p, ok
+}
+
+// Keys returns all registered keys, sorted
+func (r *ProviderRegistry) Keys() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := make([]string, 0, len(r.providers))
+	for k := range r.providers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Resolve looks up each key and pairs it with its configured weight and timeout
+func (r *ProviderRegistry) Resolve(keys []string, cfg *config.Config) ([]WeightedProvider, error) {
+	resolved := make([]WeightedProvider, 0, len(keys))
+	for _, key := range keys {
+		p, ok := r.Get(key)
+		if !ok {
+			return nil, fmt.Errorf("unknown signal provider %q (registered: %v)", key, r.Keys())
+		}
+		resolved = append(resolved, WeightedProvider{
+			Key:      key,
+			Provider: p,
+			Weight:   cfg.Scoring.Weights[key],
+			Timeout:  cfg.Signals.TimeoutFor(key),
+		})
+	}
+	return resolved, nil
+}
+
+// fetchSignal runs one provider under its own deadline and records status and latency.
+// A provider that errors is marked failed; one that overruns is abandoned and marked degraded.
+// Either way its score is zeroed so a broken integration can never vouch for an asset.
+func fetchSignal(ctx context.Context, sub *types.SubmissionData, env Env, wp WeightedProvider) types.SignalData {
+	ctx, cancel := context.WithTimeout(ctx, wp.Timeout)
+	defer cancel()
+
+	type fetchResult struct {
+		signal types.SignalData
+		err    error
+	}
+	start := time.Now()
+	done := make(chan fetchResult, 1) // buffered so an abandoned provider doesn't block forever
+	go func() {
+		signal, err := wp.Provider.Fetch(ctx, sub, env)
+		done <- fetchResult{signal, err}
+	}()
+
+	var signal types.SignalData
+	select {
+	case res := <-done:
+		signal = res.signal
+		signal.Status = types.SignalOK
+		if res.err != nil {
+			signal.Status = types.SignalFailed
+			signal.Error = res.err.Error()
+			signal.Score = 0
+		}
+	case <-ctx.Done():
+		signal = types.SignalData{
+			Status:    types.SignalDegraded,
+			Error:     ctx.Err().Error(),
+			Timestamp: env.Now(),
+		}
+	}
+
+	if signal.Evidence == nil {
+		signal.Evidence = canonicalEvidence(signal.Data)
+	}
+	signal.EvidenceHash = evidenceHash(signal.Evidence)
+	signal.LatencyMs = time.Since(start).Milliseconds()
+	return signal
+}
+
+// canonicalEvidence serializes data for providers that return no raw bytes.
+// encoding/json output is deterministic (struct fields in declaration order,
+// map keys sorted), so equal data always yields equal evidence.
+func canonicalEvidence(data interface{}) []byte {
+	if data == nil {
+		return nil
+	}
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil
+	}
+	return encoded
+}
+
+// evidenceHash commits to a signal's evidence; the zero hash if it has none
+func evidenceHash(evidence []byte) string {
+	if len(evidence) == 0 {
+		return common.Hash{}.Hex()
+	}
+	return ethcrypto.Keccak256Hash(evidence).Hex()
+}
+
+// collectSignals runs every provider concurrently and returns the signals with their
+// weighted average score. Signals that are not ok are scored according to policy.
+func collectSignals(ctx context.Context, sub *types.SubmissionData, env Env, providers []WeightedProvider, policy string) (map[string]types.SignalData, float64, error) {
+	results := make([]types.SignalData, len(providers))
+
+	var wg sync.WaitGroup
+	for i, wp := range providers {
+		wg.Add(1)
+		go func(i int, wp WeightedProvider) {
+			defer wg.Done()
+			results[i] = fetchSignal(ctx, sub, env, wp)
+		}(i, wp)
+	}
+	wg.Wait()
+
+	signals := make(map[string]types.SignalData)
+	var weighted, total float64
+	for i, wp := range providers {
+		signal := results[i]
+		signals[wp.Provider.Name()] = signal
+
+		if signal.Status != types.SignalOK {
+			switch policy {
+			case config.PolicyFail:
+				return signals, 0, fmt.Errorf("signal %s %s: %s", wp.Provider.Name(), signal.Status, signal.Error)
+			case config.PolicyExclude:
+				continue
+			}
+		}
+		weighted += signal.Score * wp.Weight
+		total += wp.Weight
+	}
+
+	if total == 0 {
+		return signals, 0, nil
+	}
+	return signals, weighted / total, nil
+}
+
+// signalCoverage is the share of the providers' total weight whose signals came
+// back ok: 1 when every configured source answered, lower as they fail or time out
+func signalCoverage(providers []WeightedProvider, signals map[string]types.SignalData) float64 {
+	var ok, total float64
+	for _, wp := range providers {
+		total += wp.Weight
+		if signals[wp.Provider.Name()].Status == types.SignalOK {
+			ok += wp.Weight
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return ok / total
+}
@@ -0,0 +1,247 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/yourorg/proptoken-oracle/internal/geo"
+	"github.com/yourorg/proptoken-oracle/internal/integrations"
+	"github.com/yourorg/proptoken-oracle/pkg/types"
+)
+
+// SatelliteProvider scores whether usable imagery exists at the coordinates.
+// The stitched image is the signal's evidence.
+type SatelliteProvider struct {
+	Client *integrations.SatelliteClient
+}
+
+func NewSatelliteProvider(client *integrations.SatelliteClient) *SatelliteProvider {
+	return &SatelliteProvider{Client: client}
+}
+
+func (p *SatelliteProvider) Name() string { return "satellite_image" }
+
+func (p *SatelliteProvider) Fetch(ctx context.Context, sub *types.SubmissionData, env Env) (types.SignalData, error) {
+	img, err := p.Client.GetSatelliteImage(ctx, sub.Location.Coordinates)
+	if err != nil {
+		return types.SignalData{Source: "SatelliteTiles", Timestamp: env.Now()}, err
+	}
+
+	score := 1.0 // Imagery fetched and shows something
+	if img.Blank {
+		score = 0
+	}
+	return types.SignalData{
+		Source:    "SatelliteTiles",
+		Score:     score,
+		Data:      img,
+		Evidence:  img.PNG,
+		Timestamp: env.Now(),
+	}, nil
+}
+
+// VisionProvider fetches imagery and scores building presence with the
+// configured image analysis backend
+type VisionProvider struct {
+	Satellite *integrations.SatelliteClient
+	Vision    *integrations.VisionClient
+}
+
+func NewVisionProvider(sat *integrations.SatelliteClient, vis *integrations.VisionClient) *VisionProvider {
+	return &VisionProvider{Satellite: sat, Vision: vis}
+}
+
+func (p *VisionProvider) Name() string { return "vision_analysis" }
+
+func (p *VisionProvider) Fetch(ctx context.Context, sub *types.SubmissionData, env Env) (types.SignalData, error) {
+	img, err := p.Satellite.GetSatelliteImage(ctx, sub.Location.Coordinates)
+	if err != nil {
+		return types.SignalData{Source: "ComputerVision", Timestamp: env.Now()}, err
+	}
+	if img.Blank {
+		// Nothing to detect a building in
+		return types.SignalData{
+			Source:    "ComputerVision",
+			Data:      "No usable imagery: " + img.Reason,
+			Evidence:  img.PNG,
+			Timestamp: env.Now(),
+		}, nil
+	}
+
+	analysis, err := p.Vision.AnalyzeImage(ctx, img.PNG)
+	if err != nil {
+		return types.SignalData{Source: "ComputerVision", Evidence: img.PNG, Timestamp: env.Now()}, err
+	}
+	return types.SignalData{
+		Source:    "ComputerVision",
+		Score:     analysis.Confidence,
+		Data:      analysis,
+		Evidence:  img.PNG,
+		Timestamp: env.Now(),
+	}, nil
+}
+
+// GeoProvider checks the coordinates fall inside the claimed city and state,
+// and not in water or a non-buildable area
+type GeoProvider struct {
+	Index *geo.Index
+}
+
+func NewGeoProvider(index *geo.Index) *GeoProvider {
+	return &GeoProvider{Index: index}
+}
+
+func (p *GeoProvider) Name() string { return "geo_validation" }
+
+func (p *GeoProvider) Fetch(ctx context.Context, sub *types.SubmissionData, env Env) (types.SignalData, error) {
+	report := p.Index.Check(sub.Location)
+	return types.SignalData{
+		Source:    "GeoJSON",
+		Score:     geoScore(report),
+		Data:      report,
+		Timestamp: env.Now(),
+	}, nil
+}
+
+// geoScore is 0 for invalid or unbuildable points; otherwise the mean of the
+// state and city checks, with a claim that can't be checked counting half
+func geoScore(r geo.Report) float64 {
+	if !r.Buildable() {
+		return 0
+	}
+	part := func(outcome string) float64 {
+		switch outcome {
+		case geo.MatchOK:
+			return 1
+		case geo.MatchUnknown:
+			return 0.5
+		}
+		return 0
+	}
+	return (part(r.StateCheck) + part(r.CityCheck)) / 2
+}
+
+// MCAProvider checks the SPV's registration status with the company registry
+type MCAProvider struct {
+	Client *integrations.MCAClient
+}
+
+func NewMCAProvider(client *integrations.MCAClient) *MCAProvider {
+	return &MCAProvider{Client: client}
+}
+
+func (p *MCAProvider) Name() string { return "mca_registry" }
+
+func (p *MCAProvider) Fetch(ctx context.Context, sub *types.SubmissionData, env Env) (types.SignalData, error) {
+	active, err := p.Client.VerifyCompany(ctx, sub.SPV.RegID)
+	mcaScore := 0.0
+	if active {
+		mcaScore = 1.0
+	}
+
+	return types.SignalData{
+		Source:    "MCA_Mock",
+		Score:     mcaScore,
+		Data:      map[string]bool{"active": active},
+		Timestamp: env.Now(),
+	}, err
+}
+
+// DeedProvider checks the title deed hash (mocked - assumes hash presence implies validity for now).
+// It calls no outside service, so there is no raw response to keep: its evidence
+// is the deed hash as submitted, and an absent hash has no evidence.
+type DeedProvider struct{}
+
+func NewDeedProvider() *DeedProvider {
+	return &DeedProvider{}
+}
+
+func (p *DeedProvider) Name() string { return "deed_integrity" }
+
+func (p *DeedProvider) Fetch(ctx context.Context, sub *types.SubmissionData, env Env) (types.SignalData, error) {
+	deedScore := 0.0
+	if sub.Documents.DeedHash != "" {
+		deedScore = 1.0
+	}
+
+	return types.SignalData{
+		Source:    "HashRegistry",
+		Score:     deedScore,
+		Data:      sub.Documents.DeedHash,
+		Evidence:  []byte(sub.Documents.DeedHash),
+		Timestamp: env.Now(),
+	}, nil
+}
+
+// UtilityProvider scores metered consumption as evidence the property is in use
+type UtilityProvider struct {
+	Client *integrations.ActivityClient
+}
+
+func NewUtilityProvider(client *integrations.ActivityClient) *UtilityProvider {
+	return &UtilityProvider{Client: client}
+}
+
+func (p *UtilityProvider) Name() string { return "utility_usage" }
+
+func (p *UtilityProvider) Fetch(ctx context.Context, sub *types.SubmissionData, env Env) (types.SignalData, error) {
+	usage, err := p.Client.GetUtilityUsage(ctx, sub.Location.Address)
+	score := usage / integrations.MinActiveUsageKWh
+	if score > 1.0 {
+		score = 1.0
+	}
+
+	return types.SignalData{
+		Source:    "UtilityBilling_Mock",
+		Score:     score,
+		Data:      map[string]float64{"kwh_90d": usage},
+		Timestamp: env.Now(),
+	}, err
+}
+
+// TaxProvider checks property tax is paid up
+type TaxProvider struct {
+	Client *integrations.ActivityClient
+}
+
+func NewTaxProvider(client *integrations.ActivityClient) *TaxProvider {
+	return &TaxProvider{Client: client}
+}
+
+func (p *TaxProvider) Name() string { return "tax_payment" }
+
+func (p *TaxProvider) Fetch(ctx context.Context, sub *types.SubmissionData, env Env) (types.SignalData, error) {
+	paid, err := p.Client.GetTaxStatus(ctx, sub.Location.Address, sub.Location.City)
+	score := 0.0
+	if paid {
+		score = 1.0
+	}
+
+	return types.SignalData{
+		Source:    "MunicipalTax_Mock",
+		Score:     score,
+		Data:      map[string]bool{"paid": paid},
+		Timestamp: env.Now(),
+	}, err
+}
+
+// OccupancyProvider reports the occupied fraction of the property
+type OccupancyProvider struct {
+	Client *integrations.ActivityClient
+}
+
+func NewOccupancyProvider(client *integrations.ActivityClient) *OccupancyProvider {
+	return &OccupancyProvider{Client: client}
+}
+
+func (p *OccupancyProvider) Name() string { return "occupancy" }
+
+func (p *OccupancyProvider) Fetch(ctx context.Context, sub *types.SubmissionData, env Env) (types.SignalData, error) {
+	rate, err := p.Client.GetOccupancyRate(ctx, sub.SPV.RegID)
+
+	return types.SignalData{
+		Source:    "RentRoll_Mock",
+		Score:     rate,
+		Data:      map[string]float64{"occupancy_rate": rate},
+		Timestamp: env.Now(),
+	}, err
+}
@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/yourorg/proptoken-oracle/pkg/types"
+)
+
+func TestDeedProviderEvidence(t *testing.T) {
+	env, err := NewEnv(types.RunInputs{ObservedAt: time.Unix(1700000000, 0).UTC()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wp := WeightedProvider{Key: "deed", Provider: NewDeedProvider(), Weight: 1, Timeout: time.Second}
+
+	sub := &types.SubmissionData{}
+	sub.Documents.DeedHash = "0xdeed"
+	signal := fetchSignal(context.Background(), sub, env, wp)
+	if string(signal.Evidence) != "0xdeed" || signal.EvidenceHash != ethcrypto.Keccak256Hash([]byte("0xdeed")).Hex() {
+		t.Errorf("evidence %q hash %s, want the submitted deed hash", signal.Evidence, signal.EvidenceHash)
+	}
+
+	signal = fetchSignal(context.Background(), &types.SubmissionData{}, env, wp)
+	if signal.EvidenceHash != (common.Hash{}).Hex() {
+		t.Errorf("evidence hash %s without a deed, want zero", signal.EvidenceHash)
+	}
+}
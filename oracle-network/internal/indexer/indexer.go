@@ -0,0 +1,286 @@
+package indexer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/yourorg/proptoken-oracle/internal/blockchain"
+	"github.com/yourorg/proptoken-oracle/internal/config"
+	"github.com/yourorg/proptoken-oracle/internal/store"
+	"github.com/yourorg/proptoken-oracle/pkg/types"
+)
+
+// Indexer mirrors AssetRegistry events into the local asset table. It backfills
+// from cfg.StartBlock in batches, then polls for new heads. Blocks within
+// cfg.ReorgDepth of the head are journaled so a reorg can be rolled back.
+type Indexer struct {
+	client *blockchain.Client
+	store  *store.Store
+	cfg    config.IndexerConfig
+	abi    *abi.ABI
+}
+
+func New(client *blockchain.Client, st *store.Store, cfg config.IndexerConfig) (*Indexer, error) {
+	parsed, err := blockchain.AssetRegistryMetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse registry abi: %v", err)
+	}
+	return &Indexer{client: client, store: st, cfg: cfg, abi: parsed}, nil
+}
+
+// Run indexes until ctx is cancelled. Errors are logged and retried on the next poll.
+func (ix *Indexer) Run(ctx context.Context) {
+	for {
+		caughtUp, err := ix.step(ctx)
+		if err != nil {
+			log.Printf("Indexer: %v", err)
+		}
+		if !caughtUp && err == nil {
+			continue // still backfilling
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(ix.cfg.PollInterval):
+		}
+	}
+}
+
+// step handles a reorg or indexes the next batch. It reports whether the head was reached.
+func (ix *Indexer) step(ctx context.Context) (bool, error) {
+	eth := ix.client.EthClient
+
+	head, err := eth.BlockNumber(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to get head: %v", err)
+	}
+
+	cursor, ok, err := ix.store.Cursor()
+	if err != nil {
+		return false, err
+	}
+	next := ix.cfg.StartBlock
+	if ok {
+		next = cursor.Number + 1
+		if cursor.Number > head {
+			// The canonical chain is now shorter than what was indexed
+			return false, ix.rollback(ctx, head)
+		}
+		if cursor.Hash != "" {
+			header, err := eth.HeaderByNumber(ctx, new(big.Int).SetUint64(cursor.Number))
+			if err != nil {
+				return false, fmt.Errorf("failed to get block %d: %v", cursor.Number, err)
+			}
+			if header.Hash().Hex() != cursor.Hash {
+				return false, ix.rollback(ctx, head)
+			}
+		}
+	}
+	if next > head {
+		return true, nil
+	}
+
+	to := next + ix.cfg.BatchSize - 1
+	if to > head {
+		to = head
+	}
+	if err := ix.index(ctx, next, to); err != nil {
+		return false, err
+	}
+
+	if head > ix.cfg.ReorgDepth {
+		if err := ix.store.PruneJournal(head - ix.cfg.ReorgDepth); err != nil {
+			return false, fmt.Errorf("failed to prune journal: %v", err)
+		}
+	}
+	return to == head, nil
+}
+
+// index applies registry events in [from, to] and moves the cursor to `to`
+func (ix *Indexer) index(ctx context.Context, from, to uint64) error {
+	eth := ix.client.EthClient
+
+	logs, err := eth.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(from),
+		ToBlock:   new(big.Int).SetUint64(to),
+		Addresses: []common.Address{ix.client.RegistryAddress},
+		Topics: [][]common.Hash{{
+			ix.abi.Events["AssetRegistered"].ID,
+			ix.abi.Events["AssetUpdated"].ID,
+			ix.abi.Events["AssetTokenized"].ID,
+		}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to fetch logs %d-%d: %v", from, to, err)
+	}
+
+	// Logs arrive in chain order; fold them into per-block asset states
+	working := make(map[string]*types.IndexedAsset)
+	var blocks []store.BlockChanges
+	for _, lg := range logs {
+		asset, err := ix.apply(ctx, working, lg)
+		if err != nil {
+			return err
+		}
+		if asset == nil {
+			continue
+		}
+		if len(blocks) == 0 || blocks[len(blocks)-1].Number != lg.BlockNumber {
+			blocks = append(blocks, store.BlockChanges{Number: lg.BlockNumber, Hash: lg.BlockHash.Hex()})
+		}
+		block := &blocks[len(blocks)-1]
+		block.Assets = append(block.Assets, *asset)
+	}
+
+	header, err := eth.HeaderByNumber(ctx, new(big.Int).SetUint64(to))
+	if err != nil {
+		return fmt.Errorf("failed to get block %d: %v", to, err)
+	}
+	if err := ix.store.ApplyBlocks(blocks, store.IndexCursor{Number: to, Hash: header.Hash().Hex()}); err != nil {
+		return fmt.Errorf("failed to store blocks %d-%d: %v", from, to, err)
+	}
+	if len(logs) > 0 {
+		log.Printf("Indexer: applied %d registry events from blocks %d-%d", len(logs), from, to)
+	}
+	return nil
+}
+
+// apply folds one event into the working set and returns the asset's new state
+func (ix *Indexer) apply(ctx context.Context, working map[string]*types.IndexedAsset, lg ethtypes.Log) (*types.IndexedAsset, error) {
+	registry := ix.client.Registry
+	if len(lg.Topics) == 0 {
+		return nil, nil
+	}
+
+	switch lg.Topics[0] {
+	case ix.abi.Events["AssetRegistered"].ID:
+		ev, err := registry.ParseAssetRegistered(lg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse AssetRegistered: %v", err)
+		}
+		asset := &types.IndexedAsset{
+			Fingerprint:     common.Hash(ev.Fingerprint).Hex(),
+			Owner:           ev.Owner.Hex(),
+			Eligible:        ev.Eligible,
+			IsMock:          ev.IsMock,
+			RegisteredAt:    time.Unix(ev.Timestamp.Int64(), 0).UTC(),
+			RegisteredBlock: lg.BlockNumber,
+		}
+		ix.enrich(ctx, asset, ev.Fingerprint, lg.BlockNumber)
+		working[asset.Fingerprint] = asset
+		return ix.touch(asset, lg), nil
+
+	case ix.abi.Events["AssetUpdated"].ID:
+		ev, err := registry.ParseAssetUpdated(lg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse AssetUpdated: %v", err)
+		}
+		asset, err := ix.load(working, common.Hash(ev.Fingerprint).Hex())
+		if err != nil {
+			return nil, err
+		}
+		asset.OracleAttestation = common.Hash(ev.NewOracleAttestation).Hex()
+		asset.ABMOutputHash = common.Hash(ev.NewAbmHash).Hex()
+		return ix.touch(asset, lg), nil
+
+	case ix.abi.Events["AssetTokenized"].ID:
+		ev, err := registry.ParseAssetTokenized(lg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse AssetTokenized: %v", err)
+		}
+		asset, err := ix.load(working, common.Hash(ev.Fingerprint).Hex())
+		if err != nil {
+			return nil, err
+		}
+		asset.Tokenized = true
+		asset.TokenAddress = ev.TokenAddress.Hex()
+		return ix.touch(asset, lg), nil
+	}
+	return nil, nil
+}
+
+// enrich fills the fields AssetRegistered doesn't carry from getAsset at that block.
+// Nodes without historical state can't serve this during backfill; the event fields are kept.
+func (ix *Indexer) enrich(ctx context.Context, asset *types.IndexedAsset, fingerprint [32]byte, block uint64) {
+	onchain, err := ix.client.Registry.GetAsset(&bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(block)}, fingerprint)
+	if err != nil {
+		log.Printf("Indexer: getAsset %s at block %d failed, storing event fields only: %v", asset.Fingerprint, block, err)
+		return
+	}
+	asset.OracleAttestation = common.Hash(onchain.OracleAttestation).Hex()
+	asset.ABMOutputHash = common.Hash(onchain.AbmOutputHash).Hex()
+	asset.ExistenceScore = onchain.ExistenceScore.String()
+	asset.OwnershipScore = onchain.OwnershipScore.String()
+	asset.FraudScore = onchain.FraudScore.String()
+	asset.RiskScore = onchain.RiskScore.String()
+}
+
+// load returns the asset from the working set or the store. An asset registered
+// before cfg.StartBlock starts out with just its fingerprint.
+func (ix *Indexer) load(working map[string]*types.IndexedAsset, fingerprint string) (*types.IndexedAsset, error) {
+	if asset, ok := working[fingerprint]; ok {
+		return asset, nil
+	}
+	asset, err := ix.store.GetAsset(fingerprint)
+	if errors.Is(err, store.ErrNotFound) {
+		asset = &types.IndexedAsset{Fingerprint: fingerprint}
+	} else if err != nil {
+		return nil, err
+	}
+	working[fingerprint] = asset
+	return asset, nil
+}
+
+// touch stamps the event's position on the asset and returns a snapshot of it
+func (ix *Indexer) touch(asset *types.IndexedAsset, lg ethtypes.Log) *types.IndexedAsset {
+	asset.UpdatedBlock = lg.BlockNumber
+	asset.LastTxHash = lg.TxHash.Hex()
+	snapshot := *asset
+	return &snapshot
+}
+
+// rollback finds the newest journaled block still on the canonical chain and
+// undoes everything after it
+func (ix *Indexer) rollback(ctx context.Context, head uint64) error {
+	journal, err := ix.store.JournalHashes()
+	if err != nil {
+		return err
+	}
+	if len(journal) == 0 {
+		return fmt.Errorf("reorg deeper than the %d-block journal; delete the asset tables and reindex", ix.cfg.ReorgDepth)
+	}
+
+	for _, block := range journal {
+		header, err := ix.client.EthClient.HeaderByNumber(ctx, new(big.Int).SetUint64(block.Number))
+		if err != nil {
+			continue // beyond the new head
+		}
+		if header.Hash().Hex() == block.Hash {
+			log.Printf("Indexer: reorg detected, rolling back to block %d", block.Number)
+			return ix.store.RollbackTo(block)
+		}
+	}
+
+	oldest := journal[len(journal)-1]
+	if oldest.Number <= ix.cfg.StartBlock {
+		log.Printf("Indexer: reorg covers the whole journal, reindexing from block %d", ix.cfg.StartBlock)
+		return ix.store.RollbackAll()
+	}
+	// Blocks below the journal had no changes to undo, so reindexing them is safe
+	to := oldest.Number - 1
+	if to > head {
+		to = head
+	}
+	log.Printf("Indexer: reorg covers the whole journal, rolling back to block %d", to)
+	return ix.store.RollbackTo(store.IndexCursor{Number: to})
+}
@@ -0,0 +1,341 @@
+package indexer
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/yourorg/proptoken-oracle/internal/blockchain"
+	"github.com/yourorg/proptoken-oracle/internal/config"
+	oraclecrypto "github.com/yourorg/proptoken-oracle/internal/crypto"
+	"github.com/yourorg/proptoken-oracle/internal/store"
+	"github.com/yourorg/proptoken-oracle/pkg/types"
+)
+
+const testKey = "b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291"
+
+var registryAddr = common.HexToAddress("0x00000000000000000000000000000000000A55E7")
+
+// emitterCode stands in for AssetRegistry: it emits whatever event the calldata
+// describes, laid out as [flag][topic0][topic1][topic2][data]. A zero flag
+// emits LOG2 with topic0 and topic1, anything else LOG3.
+var emitterCode = common.FromHex(
+	"608036036080600037" + // calldatacopy(0, 0x80, calldatasize-0x80)
+		"600035601d57" + // jumpi(log3, calldataload(0))
+		"604035602035608036036000a200" + // log2(0, size, t0, t1)
+		"5b606035604035602035608036036000a300", // log3(0, size, t0, t1, t2)
+)
+
+type testChain struct {
+	sim      *simulated.Backend
+	client   *blockchain.Client
+	signer   *oraclecrypto.KeySigner
+	chainID  *big.Int
+	nonce    uint64
+	gasPrice *big.Int
+}
+
+func newTestChain(t *testing.T) *testChain {
+	t.Helper()
+	signer, err := oraclecrypto.NewKeySigner(testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim := simulated.NewBackend(ethtypes.GenesisAlloc{
+		signer.Address(): {Balance: new(big.Int).Mul(big.NewInt(100), big.NewInt(params.Ether))},
+		registryAddr:     {Code: emitterCode, Balance: big.NewInt(0)},
+	})
+	t.Cleanup(func() { sim.Close() })
+
+	client, err := blockchain.NewClientWithBackend(sim.Client(), config.ChainConfig{RegistryAddress: registryAddr.Hex()}, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(client.Close)
+	return &testChain{sim: sim, client: client, signer: signer, chainID: client.ChainID, gasPrice: big.NewInt(10 * params.GWei)}
+}
+
+func (c *testChain) emit(t *testing.T, event string, topics []common.Hash, args ...interface{}) {
+	t.Helper()
+	parsed, _ := blockchain.AssetRegistryMetaData.GetAbi()
+	ev := parsed.Events[event]
+	data, err := ev.Inputs.NonIndexed().Pack(args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	flag := common.Hash{}
+	if len(topics) == 2 {
+		flag[31] = 1
+	}
+	calldata := append(flag.Bytes(), ev.ID.Bytes()...)
+	for i := 0; i < 2; i++ {
+		var topic common.Hash
+		if i < len(topics) {
+			topic = topics[i]
+		}
+		calldata = append(calldata, topic.Bytes()...)
+	}
+	calldata = append(calldata, data...)
+
+	tx, err := c.signer.SignTx(ethtypes.NewTransaction(c.nonce, registryAddr, big.NewInt(0), 100000, c.gasPrice, calldata), c.chainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// After a fork the pool resets asynchronously and may still see the old head
+	for attempt := 0; ; attempt++ {
+		err = c.client.EthClient.SendTransaction(context.Background(), tx)
+		if err == nil {
+			break
+		}
+		if attempt == 50 || !strings.Contains(err.Error(), "nonce too low") {
+			t.Fatal(err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	c.nonce++
+}
+
+func (c *testChain) register(t *testing.T, name string) common.Hash {
+	fingerprint := crypto.Keccak256Hash([]byte(name))
+	owner := common.HexToAddress("0x00000000000000000000000000000000000000AA")
+	c.emit(t, "AssetRegistered", []common.Hash{fingerprint, common.BytesToHash(owner.Bytes())}, true, false, big.NewInt(1700000000))
+	return fingerprint
+}
+
+// block mines the pending transactions and returns the new head's hash
+func (c *testChain) block() common.Hash {
+	return c.sim.Commit()
+}
+
+func newTestIndexer(t *testing.T, c *testChain, cfg config.IndexerConfig) (*Indexer, *store.Store) {
+	t.Helper()
+	st, err := store.Open(filepath.Join(t.TempDir(), "oracle.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { st.Close() })
+	ix, err := New(c.client, st, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ix, st
+}
+
+// catchUp steps the indexer until it reports the head was reached
+func catchUp(t *testing.T, ix *Indexer) {
+	t.Helper()
+	for i := 0; i < 100; i++ {
+		caughtUp, err := ix.step(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if caughtUp {
+			return
+		}
+	}
+	t.Fatal("indexer never caught up")
+}
+
+func assertAsset(t *testing.T, st *store.Store, fingerprint common.Hash, want bool) {
+	t.Helper()
+	_, err := st.GetAsset(fingerprint.Hex())
+	switch {
+	case want && err != nil:
+		t.Errorf("asset %s missing: %v", fingerprint.Hex(), err)
+	case !want && !errors.Is(err, store.ErrNotFound):
+		t.Errorf("asset %s present, want it rolled back (err %v)", fingerprint.Hex(), err)
+	}
+}
+
+func TestIndexerBackfill(t *testing.T) {
+	c := newTestChain(t)
+	a := c.register(t, "a")
+	c.block()
+	b := c.register(t, "b")
+	c.block()
+	c.emit(t, "AssetUpdated", []common.Hash{a}, [32]byte{1}, [32]byte{2})
+	c.block()
+	token := common.HexToAddress("0x00000000000000000000000000000000000000BB")
+	c.emit(t, "AssetTokenized", []common.Hash{b, common.BytesToHash(token.Bytes())}, big.NewInt(1700000001))
+	head := c.block()
+
+	ix, st := newTestIndexer(t, c, config.IndexerConfig{BatchSize: 2, ReorgDepth: 10})
+	catchUp(t, ix)
+
+	cursor, ok, err := st.Cursor()
+	if err != nil || !ok {
+		t.Fatalf("no cursor: %v", err)
+	}
+	if cursor.Number != 4 || cursor.Hash != head.Hex() {
+		t.Errorf("cursor = %d %s, want 4 %s", cursor.Number, cursor.Hash, head.Hex())
+	}
+
+	asset, err := st.GetAsset(a.Hex())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !asset.Eligible || asset.RegisteredBlock != 1 || asset.UpdatedBlock != 3 {
+		t.Errorf("asset a = %+v", asset)
+	}
+	if asset.OracleAttestation != common.Hash([32]byte{1}).Hex() {
+		t.Errorf("asset a attestation = %s, want the updated one", asset.OracleAttestation)
+	}
+	asset, err = st.GetAsset(b.Hex())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !asset.Tokenized || asset.TokenAddress != token.Hex() {
+		t.Errorf("asset b = %+v, want tokenized to %s", asset, token.Hex())
+	}
+}
+
+func TestIndexerRollsBackReorg(t *testing.T) {
+	c := newTestChain(t)
+	kept := c.register(t, "kept")
+	fork := c.block()
+	c.block()
+	orphaned := c.register(t, "orphaned")
+	c.block()
+
+	ix, st := newTestIndexer(t, c, config.IndexerConfig{BatchSize: 10, ReorgDepth: 10})
+	catchUp(t, ix)
+	assertAsset(t, st, orphaned, true)
+
+	// Replace blocks 2-3 with a longer side chain. The pool re-queues the
+	// orphaned registration, so outbid it with another at the same nonce.
+	if err := c.sim.Fork(fork); err != nil {
+		t.Fatal(err)
+	}
+	c.nonce, c.gasPrice = 1, big.NewInt(20*params.GWei)
+	replacement := c.register(t, "replacement")
+	for i := 0; i < 3; i++ {
+		c.block()
+	}
+
+	catchUp(t, ix)
+	assertAsset(t, st, kept, true)
+	assertAsset(t, st, orphaned, false)
+	assertAsset(t, st, replacement, true)
+
+	cursor, _, _ := st.Cursor()
+	if cursor.Number != 4 {
+		t.Errorf("cursor = %d after reindexing, want 4", cursor.Number)
+	}
+}
+
+func TestIndexerRollsBackShorterChain(t *testing.T) {
+	c := newTestChain(t)
+	kept := c.register(t, "kept")
+	fork := c.block()
+	orphaned := c.register(t, "orphaned")
+	c.block()
+	c.block()
+
+	ix, st := newTestIndexer(t, c, config.IndexerConfig{BatchSize: 10, ReorgDepth: 10})
+	catchUp(t, ix)
+
+	// The new canonical head is below the indexed cursor
+	if err := c.sim.Fork(fork); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ix.step(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	assertAsset(t, st, kept, true)
+	assertAsset(t, st, orphaned, false)
+
+	cursor, _, _ := st.Cursor()
+	if cursor.Number != 1 || cursor.Hash != fork.Hex() {
+		t.Errorf("cursor = %d %s, want 1 %s", cursor.Number, cursor.Hash, fork.Hex())
+	}
+}
+
+func TestIndexerRollsBackShorterChainBelowJournal(t *testing.T) {
+	c := newTestChain(t)
+	fork := c.block()
+	c.block()
+	c.block()
+	orphaned := c.register(t, "orphaned")
+	c.block()
+	c.block()
+
+	ix, st := newTestIndexer(t, c, config.IndexerConfig{BatchSize: 10, ReorgDepth: 10})
+	catchUp(t, ix)
+
+	// Every journaled block is above the new head
+	if err := c.sim.Fork(fork); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ix.step(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	assertAsset(t, st, orphaned, false)
+	cursor, _, _ := st.Cursor()
+	if cursor.Number != 1 {
+		t.Errorf("cursor = %d, want the new head 1", cursor.Number)
+	}
+	if _, err := ix.step(context.Background()); err != nil {
+		t.Errorf("step after rollback: %v", err)
+	}
+}
+
+func TestIndexerRollsBackWholeJournalFromGenesis(t *testing.T) {
+	c := newTestChain(t)
+	ix, st := newTestIndexer(t, c, config.IndexerConfig{BatchSize: 10, ReorgDepth: 10})
+
+	// A journal from block 0 none of whose hashes are canonical any more
+	stale := crypto.Keccak256Hash([]byte("stale"))
+	fingerprint := crypto.Keccak256Hash([]byte("gone"))
+	changes := []store.BlockChanges{{
+		Number: 0,
+		Hash:   stale.Hex(),
+		Assets: []types.IndexedAsset{{Fingerprint: fingerprint.Hex()}},
+	}}
+	if err := st.ApplyBlocks(changes, store.IndexCursor{Number: 0, Hash: stale.Hex()}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ix.rollback(context.Background(), 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, _ := st.Cursor(); ok {
+		t.Error("cursor kept after rolling back the whole journal")
+	}
+	assertAsset(t, st, fingerprint, false)
+}
+
+func TestIndexerPrunesJournal(t *testing.T) {
+	c := newTestChain(t)
+	for i := 0; i < 8; i++ {
+		c.register(t, string(rune('a'+i)))
+		c.block()
+	}
+
+	ix, st := newTestIndexer(t, c, config.IndexerConfig{BatchSize: 3, ReorgDepth: 2})
+	catchUp(t, ix)
+
+	journal, err := st.JournalHashes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(journal) == 0 {
+		t.Fatal("journal empty")
+	}
+	for _, block := range journal {
+		if block.Number < 6 {
+			t.Errorf("journal still holds block %d below head-ReorgDepth (6)", block.Number)
+		}
+	}
+}
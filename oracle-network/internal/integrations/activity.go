@@ -0,0 +1,40 @@
+package integrations
+
+import (
+    "context"
+    "strings"
+)
+
+// Utility consumption below this (kWh over the billing window) suggests a vacant property
+const MinActiveUsageKWh = 300.0
+
+type ActivityClient struct {
+    APIKey string
+}
+
+func NewActivityClient(apiKey string) *ActivityClient {
+    return &ActivityClient{APIKey: apiKey}
+}
+
+// GetUtilityUsage - FREE TIER MOCK
+// Returns electricity consumption (kWh, last 90 days) for the service address
+func (a *ActivityClient) GetUtilityUsage(ctx context.Context, address string) (float64, error) {
+    // Simulate a metered, occupied property
+    return 1250.0, nil
+}
+
+// GetTaxStatus - FREE TIER MOCK
+// Checks whether property tax is paid up for the current assessment year
+func (a *ActivityClient) GetTaxStatus(ctx context.Context, address, city string) (bool, error) {
+    // Simulate municipal records lookup
+    if strings.TrimSpace(address) == "" {
+        return false, nil
+    }
+    return true, nil
+}
+
+// GetOccupancyRate - FREE TIER MOCK
+// Returns the fraction of leasable area currently occupied (0-1)
+func (a *ActivityClient) GetOccupancyRate(ctx context.Context, regID string) (float64, error) {
+    return 0.75, nil
+}
@@ -1,6 +1,7 @@
 package integrations
 
 import (
+    "context"
     "strings"
 )
 
@@ -15,7 +16,7 @@ func NewMCAClient(baseURL, apiKey string) *MCAClient {
 
 // VerifyCompany - FREE TIER MOCK
 // Checks if the company is "active" (simulated)
-func (m *MCAClient) VerifyCompany(regID string) (bool, error) {
+func (m *MCAClient) VerifyCompany(ctx context.Context, regID string) (bool, error) {
     // Simulate API call delay
     if strings.HasPrefix(regID, "U") { // Standard Indian CIN format start
         return true, nil
@@ -1,23 +1,280 @@
 package integrations
 
 import (
+    "bytes"
+    "context"
+    "crypto/sha256"
+    "encoding/hex"
     "fmt"
+    "image"
+    "image/draw"
+    _ "image/jpeg"
+    "image/png"
+    "io"
+    "math"
+    "net/http"
+    "strconv"
+    "strings"
+    "sync"
+
+    "github.com/yourorg/proptoken-oracle/internal/config"
     "github.com/yourorg/proptoken-oracle/pkg/types"
 )
 
+// Web Mercator tiles stop short of the poles
+const maxMercatorLat = 85.05112878
+
+// Upper bound on one tile's size, so a misbehaving server can't exhaust memory
+const maxTileBytes = 4 << 20
+
+// SatelliteImage is a mosaic of tiles centred on a coordinate. PNG is the
+// stitched image; the other fields describe where it came from and whether it
+// actually shows anything.
+type SatelliteImage struct {
+    PNG      []byte  `json:"-"`
+    Zoom     int     `json:"zoom"`
+    TileX    int     `json:"tile_x"` // top-left tile
+    TileY    int     `json:"tile_y"`
+    Cols     int     `json:"cols"`
+    Rows     int     `json:"rows"`
+    Width    int     `json:"width"`
+    Height   int     `json:"height"`
+    Contrast float64 `json:"contrast"` // luminance std dev, 0-255
+    Blank    bool    `json:"blank"`
+    Reason   string  `json:"reason,omitempty"` // why the image counts as blank
+}
+
 type SatelliteClient struct {
-    APIKey string
+    TileURL      string
+    APIKey       string
+    Zoom         int
+    Radius       int
+    MinContrast  float64
+    Placeholders map[string]bool // sha256 hex of known "no imagery" tiles
+    Cache        *TileCache      // nil disables caching
+    HTTP         *http.Client
+
+    source string // cache namespace, so switching endpoints never serves stale tiles
+}
+
+func NewSatelliteClient(cfg config.ImageryConfig) *SatelliteClient {
+    placeholders := make(map[string]bool)
+    for _, h := range cfg.Placeholders {
+        placeholders[strings.ToLower(h)] = true
+    }
+    var cache *TileCache
+    if cfg.CacheDir != "" {
+        cache = NewTileCache(cfg.CacheDir, cfg.CacheTTL)
+    }
+    source := sha256.Sum256([]byte(cfg.TileURL))
+    return &SatelliteClient{
+        TileURL:      cfg.TileURL,
+        APIKey:       cfg.APIKey,
+        Zoom:         cfg.Zoom,
+        Radius:       cfg.Radius,
+        MinContrast:  cfg.MinContrast,
+        Placeholders: placeholders,
+        Cache:        cache,
+        HTTP:         &http.Client{},
+        source:       hex.EncodeToString(source[:8]),
+    }
+}
+
+// GetSatelliteImage fetches the tiles around coords at the configured zoom and
+// stitches them into one image. Missing, placeholder or featureless imagery is
+// not an error; it is reported through Blank and Reason.
+func (s *SatelliteClient) GetSatelliteImage(ctx context.Context, coords types.Coordinates) (*SatelliteImage, error) {
+    if math.Abs(coords.Lat) > maxMercatorLat || math.Abs(coords.Lng) > 180 {
+        return nil, fmt.Errorf("coordinates %f,%f outside web mercator coverage", coords.Lat, coords.Lng)
+    }
+
+    cx, cy := tileXY(coords, s.Zoom)
+    side := 2*s.Radius + 1
+    img := &SatelliteImage{
+        Zoom:  s.Zoom,
+        TileX: cx - s.Radius,
+        TileY: cy - s.Radius,
+        Cols:  side,
+        Rows:  side,
+    }
+
+    // Fetch concurrently; tiles[row*side+col] is nil where the server has none
+    tiles := make([][]byte, side*side)
+    errs := make([]error, side*side)
+    var wg sync.WaitGroup
+    for row := 0; row < side; row++ {
+        for col := 0; col < side; col++ {
+            wg.Add(1)
+            go func(i, x, y int) {
+                defer wg.Done()
+                tiles[i], errs[i] = s.tile(ctx, s.Zoom, x, y)
+            }(row*side+col, img.TileX+col, img.TileY+row)
+        }
+    }
+    wg.Wait()
+    for _, err := range errs {
+        if err != nil {
+            return nil, err
+        }
+    }
+
+    mosaic, err := stitch(tiles, side)
+    if err != nil {
+        return nil, err
+    }
+    var buf bytes.Buffer
+    if err := png.Encode(&buf, mosaic); err != nil {
+        return nil, fmt.Errorf("failed to encode image: %v", err)
+    }
+    img.PNG = buf.Bytes()
+    img.Width, img.Height = mosaic.Bounds().Dx(), mosaic.Bounds().Dy()
+    img.Contrast = math.Round(contrast(mosaic)*100) / 100
+    img.Blank, img.Reason = s.blank(tiles, img)
+    return img, nil
 }
 
-func NewSatelliteClient(apiKey string) *SatelliteClient {
-    return &SatelliteClient{APIKey: apiKey}
+// tile returns one tile from the cache or the server; nil if the server has none
+func (s *SatelliteClient) tile(ctx context.Context, z, x, y int) ([]byte, error) {
+    n := 1 << z
+    if y < 0 || y >= n {
+        return nil, nil
+    }
+    x = ((x % n) + n) % n // wrap around the antimeridian
+
+    if strings.HasPrefix(s.TileURL, config.ImageryMockPrefix) {
+        return mockTile(z, x, y)
+    }
+    if s.Cache != nil {
+        if data, ok := s.Cache.Get(s.source, z, x, y); ok {
+            return data, nil
+        }
+    }
+
+    req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.tileURL(z, x, y), nil)
+    if err != nil {
+        return nil, err
+    }
+    req.Header.Set("User-Agent", "proptoken-oracle")
+    resp, err := s.HTTP.Do(req)
+    if err != nil {
+        return nil, fmt.Errorf("failed to fetch tile %d/%d/%d: %v", z, x, y, err)
+    }
+    defer resp.Body.Close()
+
+    switch resp.StatusCode {
+    case http.StatusOK:
+    case http.StatusNotFound, http.StatusNoContent:
+        return nil, nil
+    default:
+        return nil, fmt.Errorf("tile %d/%d/%d: server returned %s", z, x, y, resp.Status)
+    }
+    data, err := io.ReadAll(io.LimitReader(resp.Body, maxTileBytes))
+    if err != nil {
+        return nil, fmt.Errorf("failed to read tile %d/%d/%d: %v", z, x, y, err)
+    }
+    if len(data) == 0 {
+        return nil, nil
+    }
+
+    if s.Cache != nil {
+        if err := s.Cache.Put(s.source, z, x, y, data); err != nil {
+            // Not fatal; the tile is simply fetched again next time
+            fmt.Printf("Tile cache: %v\n", err)
+        }
+    }
+    return data, nil
+}
+
+func (s *SatelliteClient) tileURL(z, x, y int) string {
+    zs, xs, ys := strconv.Itoa(z), strconv.Itoa(x), strconv.Itoa(y)
+    return strings.NewReplacer(
+        "{z}", zs, "{x}", xs, "{y}", ys,
+        "{TileMatrix}", zs, "{TileCol}", xs, "{TileRow}", ys,
+        "{key}", s.APIKey,
+    ).Replace(s.TileURL)
+}
+
+// blank reports whether the mosaic is unusable as evidence of a structure
+func (s *SatelliteClient) blank(tiles [][]byte, img *SatelliteImage) (bool, string) {
+    seen := make(map[string]bool)
+    for i, data := range tiles {
+        if data == nil {
+            return true, fmt.Sprintf("no imagery for tile %d/%d/%d", img.Zoom, img.TileX+i%img.Cols, img.TileY+i/img.Cols)
+        }
+        sum := sha256.Sum256(data)
+        digest := hex.EncodeToString(sum[:])
+        if s.Placeholders[digest] {
+            return true, "provider placeholder tile " + digest
+        }
+        seen[digest] = true
+    }
+    // Real imagery never repeats byte for byte across neighbouring tiles
+    if len(tiles) > 1 && len(seen) == 1 {
+        return true, "all tiles identical"
+    }
+    if img.Contrast < s.MinContrast {
+        return true, fmt.Sprintf("uniform image (contrast %.2f)", img.Contrast)
+    }
+    return false, ""
+}
+
+// tileXY is the Web Mercator tile containing coords at zoom z
+func tileXY(coords types.Coordinates, z int) (int, int) {
+    n := float64(int(1) << z)
+    lat := coords.Lat * math.Pi / 180
+    x := (coords.Lng + 180) / 360 * n
+    y := (1 - math.Asinh(math.Tan(lat))/math.Pi) / 2 * n
+    return int(math.Min(math.Floor(x), n-1)), int(math.Min(math.Floor(y), n-1))
+}
+
+// stitch decodes side x side tiles and draws them into one image, leaving
+// missing tiles transparent. Tiles must all be the same size.
+func stitch(tiles [][]byte, side int) (*image.RGBA, error) {
+    decoded := make([]image.Image, len(tiles))
+    size := image.Point{256, 256}
+    sized := false
+    for i, data := range tiles {
+        if data == nil {
+            continue
+        }
+        tile, _, err := image.Decode(bytes.NewReader(data))
+        if err != nil {
+            return nil, fmt.Errorf("failed to decode tile: %v", err)
+        }
+        b := tile.Bounds().Size()
+        if sized && b != size {
+            return nil, fmt.Errorf("tile size %v differs from %v", b, size)
+        }
+        size, sized = b, true
+        decoded[i] = tile
+    }
+
+    mosaic := image.NewRGBA(image.Rect(0, 0, size.X*side, size.Y*side))
+    for i, tile := range decoded {
+        if tile == nil {
+            continue
+        }
+        at := image.Pt(i%side*size.X, i/side*size.Y)
+        draw.Draw(mosaic, image.Rectangle{Min: at, Max: at.Add(size)}, tile, tile.Bounds().Min, draw.Src)
+    }
+    return mosaic, nil
 }
 
-// GetSatelliteImage - FREE TIER MOCK
-// Returns a static OpenStreetMap URL or a placeholder based on location
-func (s *SatelliteClient) GetSatelliteImage(coords types.Coordinates) (string, error) {
-    // In a real free tier, we might use OSM or Mapbox Free
-    // Here we simulate a successful fetch
-    url := fmt.Sprintf("https://static-maps.yandex.ru/1.x/?lang=en_US&ll=%f,%f&z=17&l=sat&size=600,450", coords.Lng, coords.Lat)
-    return url, nil
+// contrast is the standard deviation of luminance over the opaque pixels
+func contrast(img *image.RGBA) float64 {
+    var n, sum, sumSq float64
+    for i := 0; i+3 < len(img.Pix); i += 4 {
+        if img.Pix[i+3] == 0 {
+            continue
+        }
+        l := 0.299*float64(img.Pix[i]) + 0.587*float64(img.Pix[i+1]) + 0.114*float64(img.Pix[i+2])
+        n++
+        sum += l
+        sumSq += l * l
+    }
+    if n == 0 {
+        return 0
+    }
+    mean := sum / n
+    return math.Sqrt(math.Max(0, sumSq/n-mean*mean))
 }
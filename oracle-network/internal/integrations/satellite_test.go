@@ -0,0 +1,293 @@
+package integrations
+
+import (
+    "bytes"
+    "context"
+    "crypto/sha256"
+    "encoding/hex"
+    "fmt"
+    "image"
+    "image/color"
+    "image/png"
+    "net/http"
+    "net/http/httptest"
+    "os"
+    "strings"
+    "sync/atomic"
+    "testing"
+    "time"
+
+    "github.com/yourorg/proptoken-oracle/internal/config"
+    "github.com/yourorg/proptoken-oracle/pkg/types"
+)
+
+var testCoords = types.Coordinates{Lat: 19.0760, Lng: 72.8777}
+
+// tileServer serves tiles from fn at /{z}/{x}/{y}; fn returns nil for a 404
+func tileServer(t *testing.T, fn func(z, x, y int) []byte) (*httptest.Server, *atomic.Int32) {
+    t.Helper()
+    var hits atomic.Int32
+    srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        hits.Add(1)
+        var z, x, y int
+        if _, err := fmt.Sscanf(r.URL.Path, "/%d/%d/%d", &z, &x, &y); err != nil {
+            http.Error(w, "bad path", http.StatusBadRequest)
+            return
+        }
+        data := fn(z, x, y)
+        if data == nil {
+            http.NotFound(w, r)
+            return
+        }
+        w.Header().Set("Content-Type", "image/png")
+        w.Write(data)
+    }))
+    t.Cleanup(srv.Close)
+    return srv, &hits
+}
+
+func texturedTile(z, x, y int) []byte {
+    data, err := mockTile(z, x, y)
+    if err != nil {
+        panic(err)
+    }
+    return data
+}
+
+func uniformTile(gray uint8) []byte {
+    img := image.NewGray(image.Rect(0, 0, 256, 256))
+    for i := range img.Pix {
+        img.Pix[i] = gray
+    }
+    var buf bytes.Buffer
+    png.Encode(&buf, img)
+    return buf.Bytes()
+}
+
+func newTestSatellite(t *testing.T, url string, cacheDir string) *SatelliteClient {
+    t.Helper()
+    return NewSatelliteClient(config.ImageryConfig{
+        TileURL:     url,
+        Zoom:        18,
+        Radius:      1,
+        CacheDir:    cacheDir,
+        CacheTTL:    time.Hour,
+        MinContrast: 4,
+    })
+}
+
+func TestSatelliteFetchesAndStitches(t *testing.T) {
+    srv, hits := tileServer(t, texturedTile)
+    s := newTestSatellite(t, srv.URL+"/{z}/{x}/{y}", "")
+
+    img, err := s.GetSatelliteImage(context.Background(), testCoords)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if img.Blank {
+        t.Fatalf("textured mosaic counted as blank: %s", img.Reason)
+    }
+    if img.Cols != 3 || img.Rows != 3 || img.Width != 768 || img.Height != 768 {
+        t.Errorf("mosaic %dx%d tiles, %dx%d px; want 3x3, 768x768", img.Cols, img.Rows, img.Width, img.Height)
+    }
+    if got := hits.Load(); got != 9 {
+        t.Errorf("%d tile requests, want 9", got)
+    }
+
+    cx, cy := tileXY(testCoords, 18)
+    if img.TileX != cx-1 || img.TileY != cy-1 {
+        t.Errorf("top-left tile %d/%d, want %d/%d", img.TileX, img.TileY, cx-1, cy-1)
+    }
+    decoded, err := png.Decode(bytes.NewReader(img.PNG))
+    if err != nil {
+        t.Fatal(err)
+    }
+    // The centre tile lands in the middle of the mosaic
+    centre, _ := png.Decode(bytes.NewReader(texturedTile(18, cx, cy)))
+    for _, p := range []image.Point{{0, 0}, {100, 37}, {255, 255}} {
+        want := color.RGBAModel.Convert(centre.At(p.X, p.Y))
+        if got := decoded.At(256+p.X, 256+p.Y); got != want {
+            t.Errorf("mosaic pixel %v = %v, want centre tile's %v", p, got, want)
+        }
+    }
+}
+
+func TestSatelliteBlankImagery(t *testing.T) {
+    cx, cy := tileXY(testCoords, 18)
+    placeholder := uniformTile(200)
+    sum := sha256.Sum256(placeholder)
+
+    cases := []struct {
+        name   string
+        tile   func(z, x, y int) []byte
+        reason string
+    }{
+        {
+            name: "missing tile",
+            tile: func(z, x, y int) []byte {
+                if x == cx && y == cy {
+                    return nil
+                }
+                return texturedTile(z, x, y)
+            },
+            reason: "no imagery for tile",
+        },
+        {
+            name:   "placeholder",
+            tile:   func(z, x, y int) []byte { return placeholder },
+            reason: "placeholder tile",
+        },
+        {
+            name:   "identical tiles",
+            tile:   func(z, x, y int) []byte { return uniformTile(90) },
+            reason: "all tiles identical",
+        },
+        {
+            name:   "featureless",
+            tile:   func(z, x, y int) []byte { return uniformTile(uint8(100 + x%2 + y%2)) },
+            reason: "uniform image",
+        },
+    }
+    for _, tc := range cases {
+        t.Run(tc.name, func(t *testing.T) {
+            srv, _ := tileServer(t, tc.tile)
+            s := newTestSatellite(t, srv.URL+"/{z}/{x}/{y}", "")
+            s.Placeholders[hex.EncodeToString(sum[:])] = true
+
+            img, err := s.GetSatelliteImage(context.Background(), testCoords)
+            if err != nil {
+                t.Fatal(err)
+            }
+            if !img.Blank || !strings.Contains(img.Reason, tc.reason) {
+                t.Errorf("blank=%v reason=%q, want blank with %q", img.Blank, img.Reason, tc.reason)
+            }
+        })
+    }
+}
+
+func TestSatelliteServerError(t *testing.T) {
+    srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        http.Error(w, "quota exceeded", http.StatusTooManyRequests)
+    }))
+    defer srv.Close()
+
+    s := newTestSatellite(t, srv.URL+"/{z}/{x}/{y}", "")
+    if _, err := s.GetSatelliteImage(context.Background(), testCoords); err == nil {
+        t.Error("server error not reported")
+    }
+    if _, err := s.GetSatelliteImage(context.Background(), types.Coordinates{Lat: 89, Lng: 0}); err == nil {
+        t.Error("coordinates outside web mercator accepted")
+    }
+}
+
+func TestSatelliteCache(t *testing.T) {
+    srv, hits := tileServer(t, texturedTile)
+    dir := t.TempDir()
+    s := newTestSatellite(t, srv.URL+"/{z}/{x}/{y}", dir)
+
+    first, err := s.GetSatelliteImage(context.Background(), testCoords)
+    if err != nil {
+        t.Fatal(err)
+    }
+    hits.Store(0)
+
+    // Hit: nothing is fetched and the mosaic is the same
+    cached, err := s.GetSatelliteImage(context.Background(), testCoords)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if n := hits.Load(); n != 0 {
+        t.Errorf("%d requests on a warm cache, want 0", n)
+    }
+    if !bytes.Equal(first.PNG, cached.PNG) {
+        t.Error("cached mosaic differs")
+    }
+
+    // Expiry: an old tile is refetched
+    cx, cy := tileXY(testCoords, 18)
+    p := s.Cache.path(s.source, 18, cx, cy)
+    old := time.Now().Add(-2 * time.Hour)
+    if err := os.Chtimes(p, old, old); err != nil {
+        t.Fatal(err)
+    }
+    if _, err := s.GetSatelliteImage(context.Background(), testCoords); err != nil {
+        t.Fatal(err)
+    }
+    if n := hits.Load(); n != 1 {
+        t.Errorf("%d requests after one tile expired, want 1", n)
+    }
+    if info, err := os.Stat(p); err != nil || time.Since(info.ModTime()) > time.Minute {
+        t.Errorf("expired tile not rewritten: %v", err)
+    }
+
+    // A different endpoint never reads this one's tiles
+    other := newTestSatellite(t, srv.URL+"/{z}/{x}/{y}?v=2", dir)
+    if _, ok := other.Cache.Get(other.source, 18, cx, cy); ok {
+        t.Error("cache shared across tile endpoints")
+    }
+}
+
+func TestTileCachePrune(t *testing.T) {
+    c := NewTileCache(t.TempDir(), time.Hour)
+    for x := 0; x < 3; x++ {
+        if err := c.Put("src", 1, x, 0, []byte{byte(x)}); err != nil {
+            t.Fatal(err)
+        }
+    }
+    old := time.Now().Add(-2 * time.Hour)
+    os.Chtimes(c.path("src", 1, 0, 0), old, old)
+    os.Chtimes(c.path("src", 1, 1, 0), old, old)
+    leftover := c.path("src", 1, 2, 0) + "-dir"
+    os.MkdirAll(leftover, 0o755)
+    tmp := leftover + "/.tile-123"
+    os.WriteFile(tmp, []byte("partial"), 0o644)
+    os.Chtimes(tmp, old, old)
+
+    // Reading an expired tile deletes it
+    if _, ok := c.Get("src", 1, 0, 0); ok {
+        t.Error("expired tile served")
+    }
+    if _, err := os.Stat(c.path("src", 1, 0, 0)); !os.IsNotExist(err) {
+        t.Error("expired tile kept after read")
+    }
+
+    n, err := c.Prune()
+    if err != nil {
+        t.Fatal(err)
+    }
+    if n != 2 {
+        t.Errorf("pruned %d files, want the expired tile and the temp file", n)
+    }
+    if _, ok := c.Get("src", 1, 2, 0); !ok {
+        t.Error("fresh tile pruned")
+    }
+
+    empty := NewTileCache(t.TempDir()+"/missing", time.Hour)
+    if _, err := empty.Prune(); err != nil {
+        t.Errorf("prune of a missing cache dir: %v", err)
+    }
+}
+
+func TestSatelliteMockSource(t *testing.T) {
+    s := newTestSatellite(t, config.ImageryMockPrefix+"{z}/{x}/{y}", t.TempDir())
+    s.HTTP = &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+        t.Errorf("mock source made a request to %s", r.URL)
+        return nil, fmt.Errorf("no network")
+    })}
+
+    img, err := s.GetSatelliteImage(context.Background(), testCoords)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if img.Blank {
+        t.Errorf("mock imagery counted as blank: %s", img.Reason)
+    }
+    again, _ := s.GetSatelliteImage(context.Background(), testCoords)
+    if !bytes.Equal(img.PNG, again.PNG) {
+        t.Error("mock imagery not deterministic")
+    }
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
@@ -0,0 +1,125 @@
+package integrations
+
+import (
+    "context"
+    "fmt"
+    "io/fs"
+    "log"
+    "os"
+    "path/filepath"
+    "strings"
+    "time"
+)
+
+// TileCache keeps downloaded tiles on disk as <dir>/<source>/<z>/<x>/<y>.
+// A tile older than TTL is treated as missing and fetched again; expired files
+// are deleted when read and by Prune.
+type TileCache struct {
+    Dir string
+    TTL time.Duration
+}
+
+func NewTileCache(dir string, ttl time.Duration) *TileCache {
+    return &TileCache{Dir: dir, TTL: ttl}
+}
+
+func (c *TileCache) path(source string, z, x, y int) string {
+    return filepath.Join(c.Dir, source, fmt.Sprint(z), fmt.Sprint(x), fmt.Sprint(y))
+}
+
+// Get returns a cached tile, or false if it is absent or expired
+func (c *TileCache) Get(source string, z, x, y int) ([]byte, bool) {
+    p := c.path(source, z, x, y)
+    info, err := os.Stat(p)
+    if err != nil {
+        return nil, false
+    }
+    if c.expired(info) {
+        os.Remove(p)
+        return nil, false
+    }
+    data, err := os.ReadFile(p)
+    if err != nil {
+        return nil, false
+    }
+    return data, true
+}
+
+// Put stores a tile, replacing it atomically so concurrent readers never see a partial file
+func (c *TileCache) Put(source string, z, x, y int, data []byte) error {
+    p := c.path(source, z, x, y)
+    if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+        return fmt.Errorf("failed to create tile cache directory: %v", err)
+    }
+    tmp, err := os.CreateTemp(filepath.Dir(p), ".tile-*")
+    if err != nil {
+        return fmt.Errorf("failed to write tile: %v", err)
+    }
+    defer os.Remove(tmp.Name())
+    if _, err := tmp.Write(data); err != nil {
+        tmp.Close()
+        return fmt.Errorf("failed to write tile: %v", err)
+    }
+    if err := tmp.Close(); err != nil {
+        return fmt.Errorf("failed to write tile: %v", err)
+    }
+    return os.Rename(tmp.Name(), p)
+}
+
+// Prune deletes expired tiles and temp files left by interrupted writes, and
+// returns how many files it removed
+func (c *TileCache) Prune() (int, error) {
+    removed := 0
+    err := filepath.WalkDir(c.Dir, func(p string, d fs.DirEntry, err error) error {
+        if err != nil {
+            if os.IsNotExist(err) {
+                return nil
+            }
+            return err
+        }
+        if d.IsDir() {
+            return nil
+        }
+        info, err := d.Info()
+        if err != nil {
+            return nil // removed concurrently
+        }
+        // A temp file older than a minute belongs to a write that never finished
+        stale := strings.HasPrefix(d.Name(), ".tile-") && time.Since(info.ModTime()) > time.Minute
+        if c.expired(info) || stale {
+            if err := os.Remove(p); err == nil {
+                removed++
+            }
+        }
+        return nil
+    })
+    if err != nil {
+        return removed, fmt.Errorf("failed to prune tile cache: %v", err)
+    }
+    return removed, nil
+}
+
+// StartPruning runs Prune every interval until ctx is cancelled
+func (c *TileCache) StartPruning(ctx context.Context, every time.Duration) {
+    go func() {
+        ticker := time.NewTicker(every)
+        defer ticker.Stop()
+        for {
+            select {
+            case <-ctx.Done():
+                return
+            case <-ticker.C:
+                n, err := c.Prune()
+                if err != nil {
+                    log.Printf("Tile cache: %v", err)
+                } else if n > 0 {
+                    log.Printf("Tile cache: pruned %d expired tiles", n)
+                }
+            }
+        }
+    }()
+}
+
+func (c *TileCache) expired(info os.FileInfo) bool {
+    return time.Since(info.ModTime()) > c.TTL
+}
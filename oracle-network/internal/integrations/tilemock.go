@@ -0,0 +1,42 @@
+package integrations
+
+import (
+    "bytes"
+    "fmt"
+    "hash/fnv"
+    "image"
+    "image/color"
+    "image/draw"
+    "image/png"
+    "math/rand"
+)
+
+const mockTileSize = 256
+
+// mockTile draws a synthetic aerial tile for dev setups without an imagery
+// provider: textured ground with a few roof-like blocks. It is seeded by the
+// tile coordinates, so a tile always renders to the same bytes.
+func mockTile(z, x, y int) ([]byte, error) {
+    h := fnv.New64a()
+    fmt.Fprintf(h, "%d/%d/%d", z, x, y)
+    rng := rand.New(rand.NewSource(int64(h.Sum64())))
+
+    img := image.NewRGBA(image.Rect(0, 0, mockTileSize, mockTileSize))
+    for i := 0; i < len(img.Pix); i += 4 {
+        n := uint8(rng.Intn(24))
+        img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = 70+n, 90+n, 60+n, 255
+    }
+    for roofs := 3 + rng.Intn(4); roofs > 0; roofs-- {
+        w, ht := 20+rng.Intn(40), 20+rng.Intn(40)
+        x0, y0 := rng.Intn(mockTileSize-w), rng.Intn(mockTileSize-ht)
+        shade := uint8(150 + rng.Intn(80))
+        roof := &image.Uniform{color.RGBA{shade, shade - 10, shade - 20, 255}}
+        draw.Draw(img, image.Rect(x0, y0, x0+w, y0+ht), roof, image.Point{}, draw.Src)
+    }
+
+    var buf bytes.Buffer
+    if err := png.Encode(&buf, img); err != nil {
+        return nil, fmt.Errorf("failed to encode mock tile: %v", err)
+    }
+    return buf.Bytes(), nil
+}
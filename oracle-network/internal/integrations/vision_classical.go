@@ -0,0 +1,177 @@
+package integrations
+
+import (
+    "context"
+    "image"
+    "math"
+)
+
+// ClassicalAnalyzer is the model-free baseline. Roofs, walls and roads give
+// dense, sharp edges with little colour, while vegetation and water are either
+// smooth or saturated. The image is scored in square cells by Sobel edge
+// density damped by saturation; neighbouring built-up cells become boxes.
+type ClassicalAnalyzer struct {
+    Threshold     float64 // cell score from which a cell counts as built-up
+    CellSize      int     // pixels
+    EdgeThreshold float64 // Sobel magnitude (0-255 luminance) that counts as an edge
+    DenseEdges    float64 // edge density scored as fully built-up
+}
+
+func NewClassicalAnalyzer(threshold float64) *ClassicalAnalyzer {
+    return &ClassicalAnalyzer{
+        Threshold:     threshold,
+        CellSize:      16,
+        EdgeThreshold: 100,
+        DenseEdges:    0.25,
+    }
+}
+
+func (a *ClassicalAnalyzer) Name() string { return "classical" }
+
+func (a *ClassicalAnalyzer) Analyze(ctx context.Context, data []byte) (*ImageAnalysis, error) {
+    img, err := decodeRGBA(data)
+    if err != nil {
+        return nil, err
+    }
+    w, h := img.Bounds().Dx(), img.Bounds().Dy()
+
+    // Luminance and HSV saturation per pixel; transparent pixels (missing tiles) are skipped
+    luma := make([]float64, w*h)
+    sat := make([]float64, w*h)
+    opaque := make([]bool, w*h)
+    for i := range luma {
+        p := img.Pix[4*i : 4*i+4]
+        if p[3] == 0 {
+            continue
+        }
+        r, g, b := float64(p[0]), float64(p[1]), float64(p[2])
+        luma[i] = 0.299*r + 0.587*g + 0.114*b
+        hi, lo := math.Max(r, math.Max(g, b)), math.Min(r, math.Min(g, b))
+        if hi > 0 {
+            sat[i] = (hi - lo) / hi
+        }
+        opaque[i] = true
+    }
+
+    cols, rows := (w+a.CellSize-1)/a.CellSize, (h+a.CellSize-1)/a.CellSize
+    edges := make([]float64, cols*rows)
+    satSum := make([]float64, cols*rows)
+    pixels := make([]float64, cols*rows)
+    var totalEdges, totalPixels float64
+    for y := 0; y < h; y++ {
+        for x := 0; x < w; x++ {
+            i := y*w + x
+            if !opaque[i] {
+                continue
+            }
+            cell := (y/a.CellSize)*cols + x/a.CellSize
+            pixels[cell]++
+            satSum[cell] += sat[i]
+            totalPixels++
+            if x == 0 || y == 0 || x == w-1 || y == h-1 {
+                continue
+            }
+            gx := luma[i-w+1] + 2*luma[i+1] + luma[i+w+1] - luma[i-w-1] - 2*luma[i-1] - luma[i+w-1]
+            gy := luma[i+w-1] + 2*luma[i+w] + luma[i+w+1] - luma[i-w-1] - 2*luma[i-w] - luma[i-w+1]
+            if math.Hypot(gx, gy) > a.EdgeThreshold {
+                edges[cell]++
+                totalEdges++
+            }
+        }
+    }
+    if err := ctx.Err(); err != nil {
+        return nil, err
+    }
+
+    scores := make([]float64, cols*rows)
+    builtUp := make([]bool, cols*rows)
+    var built, counted int
+    for c := range scores {
+        if pixels[c] == 0 {
+            continue
+        }
+        density := edges[c] / pixels[c]
+        scores[c] = math.Min(1, density/a.DenseEdges) * (1 - satSum[c]/pixels[c])
+        builtUp[c] = scores[c] >= a.Threshold
+        counted++
+        if builtUp[c] {
+            built++
+        }
+    }
+
+    analysis := &ImageAnalysis{
+        Boxes:   a.boxes(scores, builtUp, cols, rows, w, h),
+        Metrics: map[string]float64{},
+    }
+    if totalPixels > 0 {
+        analysis.Metrics["edge_density"] = round4(totalEdges / totalPixels)
+    }
+    if counted > 0 {
+        analysis.Metrics["built_up_ratio"] = round4(float64(built) / float64(counted))
+    }
+
+    // Presence is the mean cell score around the asset
+    focus := centre(image.Rect(0, 0, w, h))
+    var sum, n float64
+    for c := range scores {
+        mid := image.Pt((c%cols)*a.CellSize+a.CellSize/2, (c/cols)*a.CellSize+a.CellSize/2)
+        if pixels[c] > 0 && mid.In(focus) {
+            sum += scores[c]
+            n++
+        }
+    }
+    if n > 0 {
+        analysis.Confidence = round4(sum / n)
+    }
+    return analysis, nil
+}
+
+// boxes groups 4-connected built-up cells and returns the bounding box of each
+// group of two or more, scored by the mean of its cells
+func (a *ClassicalAnalyzer) boxes(scores []float64, builtUp []bool, cols, rows, w, h int) []BoundingBox {
+    boxes := []BoundingBox{}
+    seen := make([]bool, len(builtUp))
+    for start := range builtUp {
+        if !builtUp[start] || seen[start] {
+            continue
+        }
+        minX, minY, maxX, maxY := cols, rows, -1, -1
+        var sum float64
+        stack := []int{start}
+        seen[start] = true
+        size := 0
+        for len(stack) > 0 {
+            c := stack[len(stack)-1]
+            stack = stack[:len(stack)-1]
+            cx, cy := c%cols, c/cols
+            minX, minY = min(minX, cx), min(minY, cy)
+            maxX, maxY = max(maxX, cx), max(maxY, cy)
+            sum += scores[c]
+            size++
+            for _, nb := range [][2]int{{cx - 1, cy}, {cx + 1, cy}, {cx, cy - 1}, {cx, cy + 1}} {
+                if nb[0] < 0 || nb[1] < 0 || nb[0] >= cols || nb[1] >= rows {
+                    continue
+                }
+                n := nb[1]*cols + nb[0]
+                if builtUp[n] && !seen[n] {
+                    seen[n] = true
+                    stack = append(stack, n)
+                }
+            }
+        }
+        if size < 2 {
+            continue
+        }
+        x0, y0 := minX*a.CellSize, minY*a.CellSize
+        x1, y1 := min((maxX+1)*a.CellSize, w), min((maxY+1)*a.CellSize, h)
+        boxes = append(boxes, BoundingBox{
+            X:          x0,
+            Y:          y0,
+            Width:      x1 - x0,
+            Height:     y1 - y0,
+            Confidence: round4(sum / float64(size)),
+            Label:      "built_up",
+        })
+    }
+    return boxes
+}
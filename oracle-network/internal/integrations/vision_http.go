@@ -0,0 +1,74 @@
+package integrations
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "fmt"
+    "io"
+    "net/http"
+    "time"
+)
+
+// Upper bound on an inference server's response
+const maxAnalysisBytes = 1 << 20
+
+// HTTPAnalyzer delegates to a model-serving endpoint. The image is POSTed as
+// the raw request body with its image/* content type; the server answers
+//
+//	{"confidence": 0.93, "boxes": [{"x": 12, "y": 40, "width": 30, "height": 22, "confidence": 0.88, "label": "building"}]}
+//
+// with confidence being building presence at the centre of the image.
+type HTTPAnalyzer struct {
+    URL       string
+    Threshold float64 // boxes below this confidence are dropped
+    Client    *http.Client
+}
+
+func NewHTTPAnalyzer(url string, threshold float64, timeout time.Duration) *HTTPAnalyzer {
+    return &HTTPAnalyzer{URL: url, Threshold: threshold, Client: &http.Client{Timeout: timeout}}
+}
+
+func (a *HTTPAnalyzer) Name() string { return "http" }
+
+func (a *HTTPAnalyzer) Analyze(ctx context.Context, image []byte) (*ImageAnalysis, error) {
+    req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.URL, bytes.NewReader(image))
+    if err != nil {
+        return nil, err
+    }
+    req.Header.Set("Content-Type", http.DetectContentType(image))
+    req.Header.Set("Accept", "application/json")
+
+    resp, err := a.Client.Do(req)
+    if err != nil {
+        return nil, err
+    }
+    defer resp.Body.Close()
+
+    body, err := io.ReadAll(io.LimitReader(resp.Body, maxAnalysisBytes))
+    if err != nil {
+        return nil, fmt.Errorf("failed to read response: %v", err)
+    }
+    if resp.StatusCode != http.StatusOK {
+        return nil, fmt.Errorf("inference server returned %s: %s", resp.Status, bytes.TrimSpace(body))
+    }
+
+    var reply struct {
+        Confidence *float64      `json:"confidence"`
+        Boxes      []BoundingBox `json:"boxes"`
+    }
+    if err := json.Unmarshal(body, &reply); err != nil {
+        return nil, fmt.Errorf("invalid response: %v", err)
+    }
+    if reply.Confidence == nil || *reply.Confidence < 0 || *reply.Confidence > 1 {
+        return nil, fmt.Errorf("response confidence missing or not in [0,1]")
+    }
+
+    analysis := &ImageAnalysis{Confidence: *reply.Confidence, Boxes: []BoundingBox{}}
+    for _, box := range reply.Boxes {
+        if box.Confidence >= a.Threshold {
+            analysis.Boxes = append(analysis.Boxes, box)
+        }
+    }
+    return analysis, nil
+}
@@ -0,0 +1,113 @@
+package integrations
+
+import (
+    "context"
+    "fmt"
+    "image"
+    "math"
+
+    "github.com/yourorg/proptoken-oracle/internal/onnx"
+)
+
+// ONNXAnalyzer runs a building classifier on the CPU with the cgo-free onnx
+// interpreter. The image is cut into non-overlapping windows the size of the
+// model input; each window scoring at least Threshold becomes a box.
+//
+// The model takes [N,3,H,W] RGB scaled to [0,1], with fixed H and W, and ends
+// in Sigmoid (one output) or Softmax over two classes, building last.
+type ONNXAnalyzer struct {
+    Model     *onnx.Model
+    Threshold float64
+
+    width, height int
+}
+
+func NewONNXAnalyzer(path string, threshold float64) (*ONNXAnalyzer, error) {
+    model, err := onnx.Load(path)
+    if err != nil {
+        return nil, err
+    }
+    s := model.InputShape
+    if len(s) != 4 || s[1] != 3 || s[2] <= 0 || s[3] <= 0 {
+        return nil, fmt.Errorf("model %s: input must be [N,3,H,W] with fixed H and W, got %v", path, s)
+    }
+    return &ONNXAnalyzer{Model: model, Threshold: threshold, width: s[3], height: s[2]}, nil
+}
+
+func (a *ONNXAnalyzer) Name() string { return "onnx" }
+
+func (a *ONNXAnalyzer) Analyze(ctx context.Context, data []byte) (*ImageAnalysis, error) {
+    img, err := decodeRGBA(data)
+    if err != nil {
+        return nil, err
+    }
+    w, h := img.Bounds().Dx(), img.Bounds().Dy()
+    if w < a.width || h < a.height {
+        return nil, fmt.Errorf("image %dx%d smaller than model input %dx%d", w, h, a.width, a.height)
+    }
+
+    focus := centre(img.Bounds())
+    analysis := &ImageAnalysis{Boxes: []BoundingBox{}}
+    windows := 0
+    for y := 0; y+a.height <= h; y += a.height {
+        for x := 0; x+a.width <= w; x += a.width {
+            if err := ctx.Err(); err != nil {
+                return nil, err
+            }
+            window := image.Rect(x, y, x+a.width, y+a.height)
+            p, err := a.classify(img, window)
+            if err != nil {
+                return nil, err
+            }
+            windows++
+            if window.Overlaps(focus) {
+                analysis.Confidence = math.Max(analysis.Confidence, p)
+            }
+            if p >= a.Threshold {
+                analysis.Boxes = append(analysis.Boxes, BoundingBox{
+                    X:          x,
+                    Y:          y,
+                    Width:      a.width,
+                    Height:     a.height,
+                    Confidence: round4(p),
+                    Label:      "building",
+                })
+            }
+        }
+    }
+    analysis.Confidence = round4(analysis.Confidence)
+    analysis.Metrics = map[string]float64{"windows": float64(windows)}
+    return analysis, nil
+}
+
+// classify returns the model's building probability for one window
+func (a *ONNXAnalyzer) classify(img *image.RGBA, window image.Rectangle) (float64, error) {
+    plane := a.width * a.height
+    input := make([]float32, 3*plane)
+    for y := 0; y < a.height; y++ {
+        for x := 0; x < a.width; x++ {
+            p := img.PixOffset(window.Min.X+x, window.Min.Y+y)
+            for c := 0; c < 3; c++ {
+                input[c*plane+y*a.width+x] = float32(img.Pix[p+c]) / 255
+            }
+        }
+    }
+
+    out, err := a.Model.Run(onnx.NewTensor([]int{1, 3, a.height, a.width}, input))
+    if err != nil {
+        return 0, err
+    }
+    var p float32
+    switch len(out.Data) {
+    case 1:
+        p = out.Data[0]
+    case 2:
+        p = out.Data[1]
+    default:
+        return 0, fmt.Errorf("model output %v is not one probability or two classes", out.Shape)
+    }
+    if p < 0 || p > 1 || math.IsNaN(float64(p)) {
+        return 0, fmt.Errorf("model output %v is not a probability", p)
+    }
+    return float64(p), nil
+}
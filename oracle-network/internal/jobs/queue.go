@@ -0,0 +1,225 @@
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/yourorg/proptoken-oracle/internal/config"
+	"github.com/yourorg/proptoken-oracle/pkg/types"
+)
+
+var ErrQueueFull = errors.New("job queue full")
+
+type Status string
+
+const (
+	StatusQueued   Status = "queued"
+	StatusRunning  Status = "running"
+	StatusSigned   Status = "signed"   // attestation signed; final if no chain is configured
+	StatusAnchored Status = "anchored" // attestation transaction sent to the registry
+	StatusFailed   Status = "failed"
+)
+
+// Job tracks one asynchronous verification
+type Job struct {
+	ID           string              `json:"id"`
+	SubmissionID string              `json:"submission_id"`
+	Status       Status              `json:"status"`
+	Error        string              `json:"error,omitempty"`
+	Result       *types.OracleResult `json:"result,omitempty"`
+	TxHash       string              `json:"tx_hash,omitempty"`
+	CallbackURL  string              `json:"callback_url,omitempty"`
+	CreatedAt    time.Time           `json:"created_at"`
+	UpdatedAt    time.Time           `json:"updated_at"`
+
+	sub  *types.SubmissionData
+	done bool
+}
+
+// Pipeline is the two-stage verification the workers drive
+// (implemented by handlers.OracleAggregator)
+type Pipeline interface {
+	Attest(ctx context.Context, sub *types.SubmissionData) (*types.OracleResult, error)
+	Anchor(ctx context.Context, sub *types.SubmissionData, result *types.OracleResult) (string, error)
+}
+
+// Queue runs verification jobs on a bounded pool of workers
+type Queue struct {
+	pipeline Pipeline
+	notifier *Notifier
+	cfg      config.JobsConfig
+
+	pending chan *Job
+
+	mu   sync.RWMutex
+	jobs map[string]*Job
+}
+
+// NewQueue creates a queue sized by cfg. notifier may be nil, in which case
+// callback URLs are ignored.
+func NewQueue(pipeline Pipeline, notifier *Notifier, cfg config.JobsConfig) *Queue {
+	return &Queue{
+		pipeline: pipeline,
+		notifier: notifier,
+		cfg:      cfg,
+		pending:  make(chan *Job, cfg.QueueSize),
+		jobs:     make(map[string]*Job),
+	}
+}
+
+// Start launches the workers; they exit when ctx is cancelled
+func (q *Queue) Start(ctx context.Context) {
+	for i := 0; i < q.cfg.Workers; i++ {
+		go q.work(ctx)
+	}
+}
+
+// Submit enqueues a submission. It fails fast with ErrQueueFull rather than blocking.
+func (q *Queue) Submit(sub *types.SubmissionData, callbackURL string) (*Job, error) {
+	id, err := newJobID()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	job := &Job{
+		ID:           id,
+		SubmissionID: sub.ID,
+		Status:       StatusQueued,
+		CallbackURL:  callbackURL,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+		sub:          sub,
+	}
+
+	q.mu.Lock()
+	q.prune(now)
+	q.jobs[id] = job
+	q.mu.Unlock()
+
+	select {
+	case q.pending <- job:
+		return q.snapshot(job), nil
+	default:
+		q.mu.Lock()
+		delete(q.jobs, id)
+		q.mu.Unlock()
+		return nil, ErrQueueFull
+	}
+}
+
+// Get returns a copy of the job's current state
+func (q *Queue) Get(id string) (*Job, bool) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	job, ok := q.jobs[id]
+	if !ok {
+		return nil, false
+	}
+	copied := *job
+	return &copied, true
+}
+
+func (q *Queue) work(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case job := <-q.pending:
+			q.run(ctx, job)
+		}
+	}
+}
+
+func (q *Queue) run(parent context.Context, job *Job) {
+	ctx, cancel := context.WithTimeout(parent, q.cfg.Timeout)
+	defer cancel()
+
+	q.update(job, func(j *Job) { j.Status = StatusRunning })
+
+	result, err := q.pipeline.Attest(ctx, job.sub)
+	if err != nil {
+		q.finish(job, func(j *Job) {
+			j.Status = StatusFailed
+			j.Error = err.Error()
+		})
+		return
+	}
+	// Anchor records the tx on result, so readers get a copy until it returns
+	signed := *result
+	q.update(job, func(j *Job) {
+		j.Status = StatusSigned
+		j.Result = &signed
+	})
+
+	txHash, err := q.pipeline.Anchor(ctx, job.sub, result)
+	q.finish(job, func(j *Job) {
+		j.Result = result
+		switch {
+		case err != nil:
+			j.Status = StatusFailed
+			j.Error = fmt.Sprintf("anchoring failed: %v", err)
+		case txHash != "":
+			j.Status = StatusAnchored
+			j.TxHash = txHash
+		}
+	})
+}
+
+func (q *Queue) update(job *Job, fn func(*Job)) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	fn(job)
+	job.UpdatedAt = time.Now()
+}
+
+// finish applies the final state and fires the callback, if any
+func (q *Queue) finish(job *Job, fn func(*Job)) {
+	q.update(job, func(j *Job) {
+		fn(j)
+		j.done = true
+	})
+	if job.CallbackURL == "" || q.notifier == nil {
+		return
+	}
+
+	snapshot := q.snapshot(job)
+	go func() {
+		if err := q.notifier.Notify(context.Background(), snapshot); err != nil {
+			log.Printf("Webhook for job %s failed: %v", snapshot.ID, err)
+		}
+	}()
+}
+
+func (q *Queue) snapshot(job *Job) *Job {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	copied := *job
+	return &copied
+}
+
+// prune drops finished jobs older than the retention window. Caller holds q.mu.
+func (q *Queue) prune(now time.Time) {
+	for id, job := range q.jobs {
+		if job.done && now.Sub(job.UpdatedAt) > q.cfg.Retention {
+			delete(q.jobs, id)
+		}
+	}
+}
+
+func newJobID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate job id: %v", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,284 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/yourorg/proptoken-oracle/internal/config"
+	"github.com/yourorg/proptoken-oracle/pkg/types"
+)
+
+// gatedPipeline blocks each stage until the test releases it
+type gatedPipeline struct {
+	attested, anchored chan string // submission IDs as each stage starts
+	release            chan struct{}
+
+	attestErr, anchorErr error
+	txHash               string
+
+	running, peak atomic.Int32
+}
+
+func newGatedPipeline() *gatedPipeline {
+	return &gatedPipeline{
+		attested: make(chan string, 16),
+		anchored: make(chan string, 16),
+		release:  make(chan struct{}),
+	}
+}
+
+func (p *gatedPipeline) Attest(ctx context.Context, sub *types.SubmissionData) (*types.OracleResult, error) {
+	n := p.running.Add(1)
+	defer p.running.Add(-1)
+	for {
+		peak := p.peak.Load()
+		if n <= peak || p.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+
+	p.attested <- sub.ID
+	select {
+	case <-p.release:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if p.attestErr != nil {
+		return nil, p.attestErr
+	}
+	return &types.OracleResult{SubmissionID: sub.ID}, nil
+}
+
+func (p *gatedPipeline) Anchor(ctx context.Context, sub *types.SubmissionData, result *types.OracleResult) (string, error) {
+	p.anchored <- sub.ID
+	select {
+	case <-p.release:
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+	return p.txHash, p.anchorErr
+}
+
+func testConfig() config.JobsConfig {
+	return config.JobsConfig{Workers: 1, QueueSize: 4, Timeout: 10 * time.Second, Retention: time.Hour}
+}
+
+func startQueue(t *testing.T, q *Queue) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	q.Start(ctx)
+}
+
+func receive(t *testing.T, ch chan string) string {
+	t.Helper()
+	select {
+	case id := <-ch:
+		return id
+	case <-time.After(5 * time.Second):
+		t.Fatal("pipeline stage not reached")
+		return ""
+	}
+}
+
+// waitStatus polls until the job reaches want
+func waitStatus(t *testing.T, q *Queue, id string, want Status) *Job {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		job, ok := q.Get(id)
+		if !ok {
+			t.Fatalf("job %s not found", id)
+		}
+		if job.Status == want {
+			return job
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job %s is %s, want %s", id, job.Status, want)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestJobLifecycle(t *testing.T) {
+	p := newGatedPipeline()
+	p.txHash = "0xabc"
+	q := NewQueue(p, nil, testConfig())
+
+	job, err := q.Submit(&types.SubmissionData{ID: "sub-1"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.Status != StatusQueued || job.SubmissionID != "sub-1" {
+		t.Fatalf("submitted job %+v", job)
+	}
+	startQueue(t, q)
+
+	receive(t, p.attested)
+	waitStatus(t, q, job.ID, StatusRunning)
+	p.release <- struct{}{}
+
+	receive(t, p.anchored)
+	signed := waitStatus(t, q, job.ID, StatusSigned)
+	if signed.Result == nil || signed.Result.SubmissionID != "sub-1" {
+		t.Errorf("signed job has result %+v", signed.Result)
+	}
+	p.release <- struct{}{}
+
+	anchored := waitStatus(t, q, job.ID, StatusAnchored)
+	if anchored.TxHash != "0xabc" || anchored.Error != "" {
+		t.Errorf("anchored job %+v", anchored)
+	}
+}
+
+func TestJobSignedWithoutChain(t *testing.T) {
+	p := newGatedPipeline()
+	q := NewQueue(p, nil, testConfig())
+	startQueue(t, q)
+
+	job, err := q.Submit(&types.SubmissionData{ID: "sub-1"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	receive(t, p.attested)
+	p.release <- struct{}{}
+	receive(t, p.anchored)
+	p.release <- struct{}{}
+
+	// Anchor returns no transaction when no chain is configured
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		q.mu.RLock()
+		done := q.jobs[job.ID].done
+		q.mu.RUnlock()
+		if done {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("job did not finish")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	final, _ := q.Get(job.ID)
+	if final.Status != StatusSigned || final.TxHash != "" {
+		t.Errorf("final job %+v, want signed without a transaction", final)
+	}
+}
+
+func TestJobFailed(t *testing.T) {
+	tests := []struct {
+		name      string
+		attestErr error
+		anchorErr error
+		wantErr   string
+	}{
+		{name: "attest", attestErr: errors.New("no consensus"), wantErr: "no consensus"},
+		{name: "anchor", anchorErr: errors.New("nonce too low"), wantErr: "anchoring failed: nonce too low"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p := newGatedPipeline()
+			p.attestErr, p.anchorErr = tc.attestErr, tc.anchorErr
+			q := NewQueue(p, nil, testConfig())
+			startQueue(t, q)
+
+			job, err := q.Submit(&types.SubmissionData{ID: "sub-1"}, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			receive(t, p.attested)
+			p.release <- struct{}{}
+			if tc.attestErr == nil {
+				receive(t, p.anchored)
+				p.release <- struct{}{}
+			}
+
+			failed := waitStatus(t, q, job.ID, StatusFailed)
+			if failed.Error != tc.wantErr {
+				t.Errorf("error %q, want %q", failed.Error, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestQueueFull(t *testing.T) {
+	cfg := testConfig()
+	cfg.QueueSize = 2
+	q := NewQueue(newGatedPipeline(), nil, cfg) // no workers, so nothing drains
+
+	for i := 0; i < cfg.QueueSize; i++ {
+		if _, err := q.Submit(&types.SubmissionData{ID: "sub"}, ""); err != nil {
+			t.Fatalf("submit %d: %v", i, err)
+		}
+	}
+	if _, err := q.Submit(&types.SubmissionData{ID: "sub"}, ""); !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("got %v, want ErrQueueFull", err)
+	}
+	if len(q.jobs) != cfg.QueueSize {
+		t.Errorf("%d jobs tracked, want the rejected one dropped", len(q.jobs))
+	}
+}
+
+func TestWorkerPoolIsBounded(t *testing.T) {
+	cfg := testConfig()
+	cfg.Workers = 2
+	p := newGatedPipeline()
+	q := NewQueue(p, nil, cfg)
+	startQueue(t, q)
+
+	for i := 0; i < 4; i++ {
+		if _, err := q.Submit(&types.SubmissionData{ID: "sub"}, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	receive(t, p.attested)
+	receive(t, p.attested)
+	select {
+	case <-p.attested:
+		t.Fatal("a third job started with two workers")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// Release everything still in the pool
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 8; i++ {
+			p.release <- struct{}{}
+		}
+	}()
+	wg.Wait()
+	if peak := p.peak.Load(); peak != 2 {
+		t.Errorf("peak of %d concurrent jobs, want 2", peak)
+	}
+}
+
+func TestFinishedJobsArePruned(t *testing.T) {
+	cfg := testConfig()
+	cfg.Retention = 10 * time.Millisecond
+	p := newGatedPipeline()
+	p.attestErr = errors.New("boom")
+	q := NewQueue(p, nil, cfg)
+	startQueue(t, q)
+
+	old, err := q.Submit(&types.SubmissionData{ID: "sub-1"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	receive(t, p.attested)
+	p.release <- struct{}{}
+	waitStatus(t, q, old.ID, StatusFailed)
+
+	time.Sleep(2 * cfg.Retention)
+	if _, err := q.Submit(&types.SubmissionData{ID: "sub-2"}, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := q.Get(old.ID); ok {
+		t.Error("finished job kept past the retention window")
+	}
+}
@@ -0,0 +1,76 @@
+package jobs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/yourorg/proptoken-oracle/internal/crypto"
+)
+
+// Notifier delivers signed job-completion webhooks. Receivers verify
+// X-Oracle-Signature (EIP-191 over the raw body) against X-Oracle-Address.
+type Notifier struct {
+	Signer   crypto.Signer
+	Client   *http.Client
+	Attempts int
+	Backoff  time.Duration
+}
+
+func NewNotifier(signer crypto.Signer, timeout time.Duration) *Notifier {
+	return &Notifier{
+		Signer:   signer,
+		Client:   &http.Client{Timeout: timeout},
+		Attempts: 3,
+		Backoff:  2 * time.Second,
+	}
+}
+
+// Notify POSTs the job to its callback URL, retrying with linear backoff
+func (n *Notifier) Notify(ctx context.Context, job *Job) error {
+	body, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	signature, err := crypto.SignPayload(n.Signer, body)
+	if err != nil {
+		return fmt.Errorf("failed to sign webhook: %v", err)
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= n.Attempts; attempt++ {
+		if lastErr = n.post(ctx, job.CallbackURL, body, signature); lastErr == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(attempt) * n.Backoff):
+		}
+	}
+	return fmt.Errorf("gave up after %d attempts: %v", n.Attempts, lastErr)
+}
+
+func (n *Notifier) post(ctx context.Context, url string, body []byte, signature string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Oracle-Signature", signature)
+	req.Header.Set("X-Oracle-Address", n.Signer.Address().Hex())
+
+	resp, err := n.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("callback returned %s", resp.Status)
+	}
+	return nil
+}
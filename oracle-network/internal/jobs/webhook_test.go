@@ -0,0 +1,130 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/yourorg/proptoken-oracle/internal/crypto"
+	"github.com/yourorg/proptoken-oracle/pkg/types"
+)
+
+const testKey = "b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291"
+
+func newTestNotifier(t *testing.T) *Notifier {
+	t.Helper()
+	signer, err := crypto.NewKeySigner(testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := NewNotifier(signer, time.Second)
+	n.Backoff = time.Millisecond
+	return n
+}
+
+// webhookReceiver records deliveries, failing the first `failures` of them
+type webhookReceiver struct {
+	failures int32
+	calls    atomic.Int32
+	jobs     chan *Job
+	errs     chan error
+}
+
+func (rcv *webhookReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if rcv.calls.Add(1) <= rcv.failures {
+		http.Error(w, "try later", http.StatusServiceUnavailable)
+		return
+	}
+	body, _ := io.ReadAll(r.Body)
+	signer, err := crypto.RecoverText(body, r.Header.Get("X-Oracle-Signature"))
+	if err == nil && signer != common.HexToAddress(r.Header.Get("X-Oracle-Address")) {
+		err = fmt.Errorf("signature recovers to %s", signer.Hex())
+	}
+	if err != nil {
+		rcv.errs <- err
+		return
+	}
+	var job Job
+	if err := json.Unmarshal(body, &job); err != nil {
+		rcv.errs <- err
+		return
+	}
+	rcv.jobs <- &job
+}
+
+func newReceiver(t *testing.T, failures int32) (*webhookReceiver, string) {
+	rcv := &webhookReceiver{failures: failures, jobs: make(chan *Job, 4), errs: make(chan error, 4)}
+	server := httptest.NewServer(rcv)
+	t.Cleanup(server.Close)
+	return rcv, server.URL
+}
+
+func TestNotifySignsAndRetries(t *testing.T) {
+	n := newTestNotifier(t)
+	rcv, url := newReceiver(t, 2)
+
+	job := &Job{ID: "job-1", SubmissionID: "sub-1", Status: StatusAnchored, TxHash: "0xabc", CallbackURL: url}
+	if err := n.Notify(context.Background(), job); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-rcv.errs:
+		t.Fatal(err)
+	case got := <-rcv.jobs:
+		if got.ID != job.ID || got.Status != StatusAnchored || got.TxHash != "0xabc" {
+			t.Errorf("delivered %+v", got)
+		}
+	}
+	if calls := rcv.calls.Load(); calls != 3 {
+		t.Errorf("%d attempts, want 3", calls)
+	}
+}
+
+func TestNotifyGivesUp(t *testing.T) {
+	n := newTestNotifier(t)
+	rcv, url := newReceiver(t, 100)
+
+	err := n.Notify(context.Background(), &Job{ID: "job-1", CallbackURL: url})
+	if err == nil || !strings.Contains(err.Error(), "gave up after 3 attempts") {
+		t.Fatalf("got %v, want to give up", err)
+	}
+	if calls := rcv.calls.Load(); calls != 3 {
+		t.Errorf("%d attempts, want 3", calls)
+	}
+}
+
+func TestQueueNotifiesOnFinish(t *testing.T) {
+	rcv, url := newReceiver(t, 0)
+	p := newGatedPipeline()
+	p.txHash = "0xabc"
+	q := NewQueue(p, newTestNotifier(t), testConfig())
+	startQueue(t, q)
+
+	job, err := q.Submit(&types.SubmissionData{ID: "sub-1"}, url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	receive(t, p.attested)
+	p.release <- struct{}{}
+	receive(t, p.anchored)
+	p.release <- struct{}{}
+
+	select {
+	case err := <-rcv.errs:
+		t.Fatal(err)
+	case got := <-rcv.jobs:
+		if got.ID != job.ID || got.Status != StatusAnchored {
+			t.Errorf("delivered %+v", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no webhook delivered")
+	}
+}
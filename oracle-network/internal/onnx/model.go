@@ -0,0 +1,505 @@
+// Package onnx is a small, cgo-free interpreter for ONNX models. It covers the
+// float32 operators of typical image classifiers (convolutions, pooling, dense
+// layers and activations), not the full operator set.
+package onnx
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// TensorProto data types this interpreter reads; all are converted to float32
+const (
+	typeFloat = 1
+	typeInt32 = 6
+	typeInt64 = 7
+)
+
+// Tensor is a dense float32 tensor in row-major order
+type Tensor struct {
+	Shape []int
+	Data  []float32
+}
+
+func NewTensor(shape []int, data []float32) *Tensor {
+	return &Tensor{Shape: shape, Data: data}
+}
+
+// Model is a parsed graph ready to run
+type Model struct {
+	Input      string
+	InputShape []int // -1 for dimensions the model leaves symbolic
+	Output     string
+
+	nodes []node
+	inits map[string]*Tensor
+}
+
+type node struct {
+	op      string
+	name    string
+	inputs  []string
+	outputs []string
+	attrs   map[string]attr
+}
+
+type attr struct {
+	f      float32
+	i      int64
+	s      []byte
+	t      *Tensor
+	floats []float32
+	ints   []int64
+}
+
+// Load reads and parses an .onnx file
+func Load(path string) (*Model, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read model %s: %v", path, err)
+	}
+	m, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse model %s: %v", path, err)
+	}
+	return m, nil
+}
+
+// Parse decodes a serialized ModelProto. The graph must have one input that is
+// not an initializer and at least one output; Run returns the first output.
+func Parse(data []byte) (*Model, error) {
+	m := &Model{inits: make(map[string]*Tensor)}
+	var graph []byte
+	err := fields(data, func(num protowire.Number, typ protowire.Type, v []byte, x uint64) error {
+		if num == 7 { // graph
+			graph = v
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if graph == nil {
+		return nil, fmt.Errorf("model has no graph")
+	}
+
+	type valueInfo struct {
+		name  string
+		shape []int
+	}
+	var inputs, outputs []valueInfo
+	err = fields(graph, func(num protowire.Number, typ protowire.Type, v []byte, x uint64) error {
+		switch num {
+		case 1: // node
+			n, err := parseNode(v)
+			if err != nil {
+				return err
+			}
+			m.nodes = append(m.nodes, n)
+		case 5: // initializer
+			name, t, err := parseTensor(v)
+			if err != nil {
+				return err
+			}
+			m.inits[name] = t
+		case 11, 12: // input, output
+			name, shape, err := parseValueInfo(v)
+			if err != nil {
+				return err
+			}
+			if num == 11 {
+				inputs = append(inputs, valueInfo{name, shape})
+			} else {
+				outputs = append(outputs, valueInfo{name, shape})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, in := range inputs {
+		if _, ok := m.inits[in.name]; ok {
+			continue
+		}
+		if m.Input != "" {
+			return nil, fmt.Errorf("model has more than one input")
+		}
+		m.Input, m.InputShape = in.name, in.shape
+	}
+	if m.Input == "" || len(outputs) == 0 {
+		return nil, fmt.Errorf("model needs one input and an output")
+	}
+	m.Output = outputs[0].name
+
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// validate checks that every node is a supported operator with an accepted
+// number of inputs, that nodes only read values defined before them, and that
+// the output is produced
+func (m *Model) validate() error {
+	defined := map[string]bool{m.Input: true}
+	for name := range m.inits {
+		defined[name] = true
+	}
+	for _, n := range m.nodes {
+		o, ok := ops[n.op]
+		if !ok {
+			return fmt.Errorf("unsupported operator %s", n.op)
+		}
+		if len(n.inputs) < o.minIn || len(n.inputs) > o.maxIn {
+			return fmt.Errorf("%s %s: %v", n.op, n.name, arityError(o, len(n.inputs)))
+		}
+		for i, name := range n.inputs {
+			if name == "" {
+				if i < o.minIn {
+					return fmt.Errorf("%s %s: required input %d missing", n.op, n.name, i)
+				}
+				continue
+			}
+			if !defined[name] {
+				return fmt.Errorf("%s %s: input %s is not defined before the node", n.op, n.name, name)
+			}
+		}
+		if len(n.outputs) == 0 {
+			return fmt.Errorf("%s %s: node has no outputs", n.op, n.name)
+		}
+		for _, name := range n.outputs {
+			if name == "" {
+				continue
+			}
+			if defined[name] {
+				return fmt.Errorf("%s %s: output %s is already defined", n.op, n.name, name)
+			}
+			defined[name] = true
+		}
+	}
+	if !defined[m.Output] {
+		return fmt.Errorf("output %s is never produced", m.Output)
+	}
+	return nil
+}
+
+// Run evaluates the graph on input and returns the first graph output
+func (m *Model) Run(input *Tensor) (*Tensor, error) {
+	if err := checkTensor(input); err != nil {
+		return nil, fmt.Errorf("input: %v", err)
+	}
+	if len(m.InputShape) > 0 {
+		if len(input.Shape) != len(m.InputShape) {
+			return nil, fmt.Errorf("input shape %v does not match model input %v", input.Shape, m.InputShape)
+		}
+		for i, d := range m.InputShape {
+			if d >= 0 && input.Shape[i] != d {
+				return nil, fmt.Errorf("input shape %v does not match model input %v", input.Shape, m.InputShape)
+			}
+		}
+	}
+	values := make(map[string]*Tensor, len(m.inits)+len(m.nodes)+1)
+	for name, t := range m.inits {
+		values[name] = t
+	}
+	values[m.Input] = input
+
+	// ONNX graphs are stored in topological order
+	for _, n := range m.nodes {
+		in := make([]*Tensor, len(n.inputs))
+		for i, name := range n.inputs {
+			if name == "" {
+				continue // omitted optional input
+			}
+			t, ok := values[name]
+			if !ok {
+				return nil, fmt.Errorf("%s %s: input %s not computed", n.op, n.name, name)
+			}
+			in[i] = t
+		}
+		out, err := ops[n.op].apply(n, in)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: %v", n.op, n.name, err)
+		}
+		for i, name := range n.outputs {
+			if i < len(out) && name != "" {
+				values[name] = out[i]
+			}
+		}
+	}
+
+	out, ok := values[m.Output]
+	if !ok {
+		return nil, fmt.Errorf("output %s not computed", m.Output)
+	}
+	return out, nil
+}
+
+func parseNode(b []byte) (node, error) {
+	n := node{attrs: make(map[string]attr)}
+	err := fields(b, func(num protowire.Number, typ protowire.Type, v []byte, x uint64) error {
+		switch num {
+		case 1:
+			n.inputs = append(n.inputs, string(v))
+		case 2:
+			n.outputs = append(n.outputs, string(v))
+		case 3:
+			n.name = string(v)
+		case 4:
+			n.op = string(v)
+		case 5:
+			name, a, err := parseAttr(v)
+			if err != nil {
+				return err
+			}
+			n.attrs[name] = a
+		case 7:
+			if d := string(v); d != "" && d != "ai.onnx" {
+				return fmt.Errorf("operator domain %s not supported", d)
+			}
+		}
+		return nil
+	})
+	return n, err
+}
+
+func parseAttr(b []byte) (string, attr, error) {
+	var (
+		name string
+		a    attr
+	)
+	err := fields(b, func(num protowire.Number, typ protowire.Type, v []byte, x uint64) error {
+		var err error
+		switch num {
+		case 1:
+			name = string(v)
+		case 2:
+			a.f = math.Float32frombits(uint32(x))
+		case 3:
+			a.i = int64(x)
+		case 4:
+			a.s = v
+		case 5:
+			_, a.t, err = parseTensor(v)
+		case 7:
+			a.floats, err = appendFloats(a.floats, typ, v, x)
+		case 8:
+			a.ints, err = appendInts(a.ints, typ, v, x)
+		}
+		return err
+	})
+	return name, a, err
+}
+
+func parseTensor(b []byte) (string, *Tensor, error) {
+	var (
+		name     string
+		dataType uint64
+		dims     []int64
+		floats   []float32
+		ints     []int64
+		raw      []byte
+	)
+	err := fields(b, func(num protowire.Number, typ protowire.Type, v []byte, x uint64) error {
+		var err error
+		switch num {
+		case 1:
+			dims, err = appendInts(dims, typ, v, x)
+		case 2:
+			dataType = x
+		case 4:
+			floats, err = appendFloats(floats, typ, v, x)
+		case 5, 7: // int32_data, int64_data
+			ints, err = appendInts(ints, typ, v, x)
+		case 8:
+			name = string(v)
+		case 9:
+			raw = v
+		case 14:
+			return fmt.Errorf("tensor stored as external data")
+		}
+		return err
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	t := &Tensor{Shape: make([]int, len(dims))}
+	for i, d := range dims {
+		t.Shape[i] = int(d)
+	}
+
+	switch dataType {
+	case typeFloat:
+		if raw != nil {
+			for i := 0; i+4 <= len(raw); i += 4 {
+				floats = append(floats, math.Float32frombits(binary.LittleEndian.Uint32(raw[i:])))
+			}
+		}
+		t.Data = floats
+	case typeInt32, typeInt64:
+		width := 8
+		if dataType == typeInt32 {
+			width = 4
+		}
+		for i := 0; i+width <= len(raw); i += width {
+			if width == 4 {
+				ints = append(ints, int64(int32(binary.LittleEndian.Uint32(raw[i:]))))
+			} else {
+				ints = append(ints, int64(binary.LittleEndian.Uint64(raw[i:])))
+			}
+		}
+		t.Data = make([]float32, len(ints))
+		for i, v := range ints {
+			t.Data[i] = float32(v)
+		}
+	default:
+		return "", nil, fmt.Errorf("tensor %s: data type %d not supported", name, dataType)
+	}
+
+	if err := checkTensor(t); err != nil {
+		return "", nil, fmt.Errorf("tensor %s: %v", name, err)
+	}
+	return name, t, nil
+}
+
+// parseValueInfo returns a graph input or output's name and tensor shape
+func parseValueInfo(b []byte) (string, []int, error) {
+	var (
+		name  string
+		shape []int
+	)
+	err := fields(b, func(num protowire.Number, typ protowire.Type, v []byte, x uint64) error {
+		switch num {
+		case 1:
+			name = string(v)
+		case 2: // TypeProto.tensor_type.shape.dim
+			return fields(v, func(num protowire.Number, typ protowire.Type, v []byte, x uint64) error {
+				if num != 1 {
+					return nil
+				}
+				return fields(v, func(num protowire.Number, typ protowire.Type, v []byte, x uint64) error {
+					if num != 2 {
+						return nil
+					}
+					return fields(v, func(num protowire.Number, typ protowire.Type, v []byte, x uint64) error {
+						if num != 1 {
+							return nil
+						}
+						dim := -1
+						fields(v, func(num protowire.Number, typ protowire.Type, v []byte, x uint64) error {
+							if num == 1 {
+								dim = int(x)
+							}
+							return nil
+						})
+						shape = append(shape, dim)
+						return nil
+					})
+				})
+			})
+		}
+		return nil
+	})
+	return name, shape, err
+}
+
+// fields calls fn for each field of a serialized message. v is set for
+// length-delimited fields, x for varint and fixed-width ones.
+func fields(b []byte, fn func(num protowire.Number, typ protowire.Type, v []byte, x uint64) error) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		var (
+			v []byte
+			x uint64
+		)
+		switch typ {
+		case protowire.VarintType:
+			x, n = protowire.ConsumeVarint(b)
+		case protowire.Fixed32Type:
+			var u uint32
+			u, n = protowire.ConsumeFixed32(b)
+			x = uint64(u)
+		case protowire.Fixed64Type:
+			x, n = protowire.ConsumeFixed64(b)
+		case protowire.BytesType:
+			v, n = protowire.ConsumeBytes(b)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, b)
+		}
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		if err := fn(num, typ, v, x); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appendInts reads a repeated int64 field, packed or not
+func appendInts(dst []int64, typ protowire.Type, v []byte, x uint64) ([]int64, error) {
+	if typ != protowire.BytesType {
+		return append(dst, int64(x)), nil
+	}
+	for len(v) > 0 {
+		u, n := protowire.ConsumeVarint(v)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		dst = append(dst, int64(u))
+		v = v[n:]
+	}
+	return dst, nil
+}
+
+// appendFloats reads a repeated float field, packed or not
+func appendFloats(dst []float32, typ protowire.Type, v []byte, x uint64) ([]float32, error) {
+	if typ != protowire.BytesType {
+		return append(dst, math.Float32frombits(uint32(x))), nil
+	}
+	if len(v)%4 != 0 {
+		return nil, fmt.Errorf("packed float field has %d bytes", len(v))
+	}
+	for i := 0; i < len(v); i += 4 {
+		dst = append(dst, math.Float32frombits(binary.LittleEndian.Uint32(v[i:])))
+	}
+	return dst, nil
+}
+
+// checkTensor reports a tensor whose data does not fill its shape
+func checkTensor(t *Tensor) error {
+	if t == nil {
+		return fmt.Errorf("missing tensor")
+	}
+	for _, d := range t.Shape {
+		if d < 0 {
+			return fmt.Errorf("shape %v has a negative dimension", t.Shape)
+		}
+	}
+	if len(t.Data) != product(t.Shape) {
+		return fmt.Errorf("%d values do not fill shape %v", len(t.Data), t.Shape)
+	}
+	return nil
+}
+
+func product(shape []int) int {
+	p := 1
+	for _, d := range shape {
+		p *= d
+	}
+	return p
+}
@@ -0,0 +1,203 @@
+package onnx
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// message builds a serialized protobuf message from encoded fields
+func message(fields ...[]byte) []byte {
+	var b []byte
+	for _, f := range fields {
+		b = append(b, f...)
+	}
+	return b
+}
+
+func bytesField(num protowire.Number, v []byte) []byte {
+	b := protowire.AppendTag(nil, num, protowire.BytesType)
+	return protowire.AppendBytes(b, v)
+}
+
+func stringField(num protowire.Number, s string) []byte {
+	return bytesField(num, []byte(s))
+}
+
+func varintField(num protowire.Number, x uint64) []byte {
+	b := protowire.AppendTag(nil, num, protowire.VarintType)
+	return protowire.AppendVarint(b, x)
+}
+
+func encodeTensor(name string, shape []int, data ...float32) []byte {
+	fields := [][]byte{stringField(8, name), varintField(2, typeFloat)}
+	for _, d := range shape {
+		fields = append(fields, varintField(1, uint64(d)))
+	}
+	var packed []byte
+	for _, v := range data {
+		packed = protowire.AppendFixed32(packed, math.Float32bits(v))
+	}
+	return message(append(fields, bytesField(4, packed))...)
+}
+
+// encodeValueInfo encodes a graph input or output; dims below 0 are left symbolic
+func encodeValueInfo(name string, shape ...int) []byte {
+	var dims [][]byte
+	for _, d := range shape {
+		if d < 0 {
+			dims = append(dims, bytesField(1, stringField(2, "batch")))
+		} else {
+			dims = append(dims, bytesField(1, varintField(1, uint64(d))))
+		}
+	}
+	tensorType := message(varintField(1, typeFloat), bytesField(2, message(dims...)))
+	return message(stringField(1, name), bytesField(2, bytesField(1, tensorType)))
+}
+
+func encodeNode(op string, inputs, outputs []string, attrs ...[]byte) []byte {
+	fields := [][]byte{stringField(3, strings.ToLower(op)), stringField(4, op)}
+	for _, in := range inputs {
+		fields = append(fields, stringField(1, in))
+	}
+	for _, out := range outputs {
+		fields = append(fields, stringField(2, out))
+	}
+	for _, a := range attrs {
+		fields = append(fields, bytesField(5, a))
+	}
+	return message(fields...)
+}
+
+func encodeModel(graph ...[]byte) []byte {
+	return bytesField(7, message(graph...))
+}
+
+// classifier is x[batch,2] -> Gemm(W, B) -> Relu -> Softmax
+func classifier(nodes ...[]byte) []byte {
+	if nodes == nil {
+		nodes = [][]byte{
+			encodeNode("Gemm", []string{"x", "W", "B"}, []string{"h"}, message(stringField(1, "transB"), varintField(3, 1))),
+			encodeNode("Relu", []string{"h"}, []string{"r"}),
+			encodeNode("Softmax", []string{"r"}, []string{"y"}),
+		}
+	}
+	graph := [][]byte{
+		bytesField(5, encodeTensor("W", []int{2, 2}, 1, 0, 0, 2)),
+		bytesField(5, encodeTensor("B", []int{2}, 0, -1)),
+		bytesField(11, encodeValueInfo("x", -1, 2)),
+		bytesField(11, encodeValueInfo("W", 2, 2)),
+		bytesField(12, encodeValueInfo("y", -1, 2)),
+	}
+	for _, n := range nodes {
+		graph = append(graph, bytesField(1, n))
+	}
+	return encodeModel(graph...)
+}
+
+func TestParseAndRun(t *testing.T) {
+	m, err := Parse(classifier())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Input != "x" || m.Output != "y" {
+		t.Fatalf("input %s, output %s", m.Input, m.Output)
+	}
+	if len(m.InputShape) != 2 || m.InputShape[0] != -1 || m.InputShape[1] != 2 {
+		t.Fatalf("input shape %v", m.InputShape)
+	}
+
+	// h is [1, 1] for the first row, and [-1, -1] clamps to [0, 0] for the second
+	out, err := m.Run(NewTensor([]int{2, 2}, []float32{1, 1, -1, 0}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertTensor(t, out, tensor([]int{2, 2}, 0.5, 0.5, 0.5, 0.5))
+
+	out, err = m.Run(NewTensor([]int{1, 2}, []float32{0, 1}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := float32(math.E)
+	assertTensor(t, out, tensor([]int{1, 2}, 1/(1+e), e/(1+e)))
+}
+
+func TestRunRejectsBadInput(t *testing.T) {
+	m, err := Parse(classifier())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		input   *Tensor
+		wantErr string
+	}{
+		{"nil", nil, "missing tensor"},
+		{"short data", NewTensor([]int{1, 2}, []float32{1}), "do not fill shape"},
+		{"negative dimension", NewTensor([]int{-1, -2}, []float32{1, 2}), "negative dimension"},
+		{"wrong rank", NewTensor([]int{2}, []float32{1, 2}), "does not match model input"},
+		{"wrong width", NewTensor([]int{1, 3}, []float32{1, 2, 3}), "does not match model input"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := m.Run(tc.input)
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("got error %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseRejectsBadGraphs(t *testing.T) {
+	relu := encodeNode("Relu", []string{"x"}, []string{"y"})
+	tests := []struct {
+		name    string
+		model   []byte
+		wantErr string
+	}{
+		{"no graph", nil, "no graph"},
+		{"unknown operator", classifier(encodeNode("Erf", []string{"x"}, []string{"y"})), "unsupported operator Erf"},
+		{"too few inputs", classifier(encodeNode("Add", []string{"x"}, []string{"y"})), "takes 2 inputs, got 1"},
+		{"too many inputs", classifier(encodeNode("Relu", []string{"x", "W"}, []string{"y"})), "takes 1 inputs, got 2"},
+		{"omitted required input", classifier(encodeNode("Gemm", []string{"", "W"}, []string{"y"})), "required input 0 missing"},
+		{"undefined input", classifier(encodeNode("Relu", []string{"z"}, []string{"y"})), "input z is not defined"},
+		{
+			name: "out of order",
+			model: classifier(
+				encodeNode("Relu", []string{"h"}, []string{"y"}),
+				encodeNode("Relu", []string{"x"}, []string{"h"}),
+			),
+			wantErr: "input h is not defined",
+		},
+		{"no outputs", classifier(encodeNode("Relu", []string{"x"}, nil), relu), "has no outputs"},
+		{
+			name: "redefined value",
+			model: classifier(
+				encodeNode("Relu", []string{"x"}, []string{"W"}),
+				relu,
+			),
+			wantErr: "output W is already defined",
+		},
+		{"output never produced", classifier(encodeNode("Relu", []string{"x"}, []string{"z"})), "output y is never produced"},
+		{
+			name: "initializer short of its shape",
+			model: encodeModel(
+				bytesField(5, encodeTensor("W", []int{2, 2}, 1, 2, 3)),
+				bytesField(11, encodeValueInfo("x", 2)),
+				bytesField(12, encodeValueInfo("y", 2)),
+				bytesField(1, relu),
+			),
+			wantErr: "do not fill shape",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Parse(tc.model)
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("got error %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,559 @@
+package onnx
+
+import (
+	"fmt"
+	"math"
+)
+
+type opFunc func(n node, in []*Tensor) ([]*Tensor, error)
+
+// op is an operator and the number of inputs it takes. The first minIn inputs
+// are required; the rest are optional and may be omitted (nil).
+type op struct {
+	run          opFunc
+	minIn, maxIn int
+}
+
+var ops map[string]op
+
+func init() {
+	ops = map[string]op{
+		"Identity": {passThrough, 1, 1},
+		"Dropout":  {passThrough, 1, 3}, // inference: no-op
+		"Constant": {constant, 0, 0},
+
+		"Relu":      {unary(func(x float32) float32 { return float32(math.Max(float64(x), 0)) }), 1, 1},
+		"Sigmoid":   {unary(func(x float32) float32 { return float32(1 / (1 + math.Exp(-float64(x)))) }), 1, 1},
+		"Tanh":      {unary(func(x float32) float32 { return float32(math.Tanh(float64(x))) }), 1, 1},
+		"LeakyRelu": {leakyRelu, 1, 1},
+
+		"Add": {elementwise(func(a, b float32) float32 { return a + b }), 2, 2},
+		"Sub": {elementwise(func(a, b float32) float32 { return a - b }), 2, 2},
+		"Mul": {elementwise(func(a, b float32) float32 { return a * b }), 2, 2},
+		"Div": {elementwise(func(a, b float32) float32 { return a / b }), 2, 2},
+
+		"Conv":               {conv, 2, 3},
+		"MaxPool":            {pool(true), 1, 1},
+		"AveragePool":        {pool(false), 1, 1},
+		"GlobalAveragePool":  {globalAveragePool, 1, 1},
+		"BatchNormalization": {batchNorm, 5, 5},
+		"Flatten":            {flatten, 1, 1},
+		"Reshape":            {reshape, 2, 2},
+		"Gemm":               {gemm, 2, 3},
+		"MatMul":             {matMul, 2, 2},
+		"Softmax":            {softmax, 1, 1},
+	}
+}
+
+// apply checks n's inputs against the operator's arity and runs it
+func (o op) apply(n node, in []*Tensor) ([]*Tensor, error) {
+	if len(in) < o.minIn || len(in) > o.maxIn {
+		return nil, arityError(o, len(in))
+	}
+	for i := 0; i < o.minIn; i++ {
+		if in[i] == nil {
+			return nil, fmt.Errorf("required input %d missing", i)
+		}
+	}
+	return o.run(n, in)
+}
+
+func arityError(o op, got int) error {
+	if o.minIn == o.maxIn {
+		return fmt.Errorf("takes %d inputs, got %d", o.minIn, got)
+	}
+	return fmt.Errorf("takes %d to %d inputs, got %d", o.minIn, o.maxIn, got)
+}
+
+// positive checks that every value of an int list attribute is at least 1
+func positive(name string, values []int) error {
+	for _, v := range values {
+		if v < 1 {
+			return fmt.Errorf("%s %v must be positive", name, values)
+		}
+	}
+	return nil
+}
+
+func nonNegative(name string, values []int) error {
+	for _, v := range values {
+		if v < 0 {
+			return fmt.Errorf("%s %v must not be negative", name, values)
+		}
+	}
+	return nil
+}
+
+// optional returns in[i], or nil if the input was omitted
+func optional(in []*Tensor, i int) *Tensor {
+	if i < len(in) {
+		return in[i]
+	}
+	return nil
+}
+
+func (n node) intAttr(name string, def int64) int64 {
+	if a, ok := n.attrs[name]; ok {
+		return a.i
+	}
+	return def
+}
+
+func (n node) floatAttr(name string, def float32) float32 {
+	if a, ok := n.attrs[name]; ok {
+		return a.f
+	}
+	return def
+}
+
+// intsAttr returns an int list attribute, or size copies of def if it is absent
+func (n node) intsAttr(name string, size int, def int) []int {
+	out := make([]int, size)
+	a, ok := n.attrs[name]
+	for i := range out {
+		out[i] = def
+		if ok && i < len(a.ints) {
+			out[i] = int(a.ints[i])
+		}
+	}
+	return out
+}
+
+func passThrough(n node, in []*Tensor) ([]*Tensor, error) {
+	return []*Tensor{in[0]}, nil
+}
+
+func constant(n node, in []*Tensor) ([]*Tensor, error) {
+	a, ok := n.attrs["value"]
+	if !ok || a.t == nil {
+		return nil, fmt.Errorf("only tensor constants are supported")
+	}
+	return []*Tensor{a.t}, nil
+}
+
+func unary(f func(float32) float32) opFunc {
+	return func(n node, in []*Tensor) ([]*Tensor, error) {
+		x := in[0]
+		out := &Tensor{Shape: x.Shape, Data: make([]float32, len(x.Data))}
+		for i, v := range x.Data {
+			out.Data[i] = f(v)
+		}
+		return []*Tensor{out}, nil
+	}
+}
+
+func leakyRelu(n node, in []*Tensor) ([]*Tensor, error) {
+	alpha := n.floatAttr("alpha", 0.01)
+	return unary(func(x float32) float32 {
+		if x < 0 {
+			return alpha * x
+		}
+		return x
+	})(n, in)
+}
+
+func elementwise(f func(a, b float32) float32) opFunc {
+	return func(n node, in []*Tensor) ([]*Tensor, error) {
+		out, err := broadcast(in[0], in[1], f)
+		if err != nil {
+			return nil, err
+		}
+		return []*Tensor{out}, nil
+	}
+}
+
+// broadcast applies f elementwise under numpy broadcasting rules
+func broadcast(a, b *Tensor, f func(a, b float32) float32) (*Tensor, error) {
+	rank := len(a.Shape)
+	if len(b.Shape) > rank {
+		rank = len(b.Shape)
+	}
+	as, bs := padShape(a.Shape, rank), padShape(b.Shape, rank)
+	shape := make([]int, rank)
+	for i := range shape {
+		switch {
+		case as[i] == bs[i], bs[i] == 1:
+			shape[i] = as[i]
+		case as[i] == 1:
+			shape[i] = bs[i]
+		default:
+			return nil, fmt.Errorf("cannot broadcast %v with %v", a.Shape, b.Shape)
+		}
+	}
+
+	aStride, bStride := broadcastStrides(as), broadcastStrides(bs)
+	out := &Tensor{Shape: shape, Data: make([]float32, product(shape))}
+	idx := make([]int, rank)
+	for i := range out.Data {
+		ao, bo := 0, 0
+		for d, v := range idx {
+			ao += v * aStride[d]
+			bo += v * bStride[d]
+		}
+		out.Data[i] = f(a.Data[ao], b.Data[bo])
+		for d := rank - 1; d >= 0; d-- {
+			idx[d]++
+			if idx[d] < shape[d] {
+				break
+			}
+			idx[d] = 0
+		}
+	}
+	return out, nil
+}
+
+func padShape(shape []int, rank int) []int {
+	padded := make([]int, rank)
+	for i := range padded {
+		padded[i] = 1
+	}
+	copy(padded[rank-len(shape):], shape)
+	return padded
+}
+
+// broadcastStrides are row-major strides, zero along dimensions of size 1
+func broadcastStrides(shape []int) []int {
+	strides := make([]int, len(shape))
+	step := 1
+	for d := len(shape) - 1; d >= 0; d-- {
+		if shape[d] != 1 {
+			strides[d] = step
+		}
+		step *= shape[d]
+	}
+	return strides
+}
+
+// conv is a 2D convolution over NCHW input with OIHW weights
+func conv(n node, in []*Tensor) ([]*Tensor, error) {
+	x, w, bias := in[0], in[1], optional(in, 2)
+	if len(x.Shape) != 4 || len(w.Shape) != 4 {
+		return nil, fmt.Errorf("only 2D convolutions are supported")
+	}
+	if pad := string(n.attrs["auto_pad"].s); pad != "" && pad != "NOTSET" && pad != "VALID" {
+		return nil, fmt.Errorf("auto_pad %s not supported", pad)
+	}
+
+	batch, channels, height, width := x.Shape[0], x.Shape[1], x.Shape[2], x.Shape[3]
+	filters, groupChannels, kh, kw := w.Shape[0], w.Shape[1], w.Shape[2], w.Shape[3]
+	group := int(n.intAttr("group", 1))
+	if group < 1 || channels != groupChannels*group || filters%group != 0 {
+		return nil, fmt.Errorf("input %v does not match weights %v with group %d", x.Shape, w.Shape, group)
+	}
+	if bias != nil && (len(bias.Shape) != 1 || len(bias.Data) != filters) {
+		return nil, fmt.Errorf("bias %v does not match %d filters", bias.Shape, filters)
+	}
+	strides := n.intsAttr("strides", 2, 1)
+	dilations := n.intsAttr("dilations", 2, 1)
+	pads := n.intsAttr("pads", 4, 0) // top, left, bottom, right
+	if err := positive("strides", strides); err != nil {
+		return nil, err
+	}
+	if err := positive("dilations", dilations); err != nil {
+		return nil, err
+	}
+	if err := nonNegative("pads", pads); err != nil {
+		return nil, err
+	}
+
+	outH := (height+pads[0]+pads[2]-dilations[0]*(kh-1)-1)/strides[0] + 1
+	outW := (width+pads[1]+pads[3]-dilations[1]*(kw-1)-1)/strides[1] + 1
+	if outH <= 0 || outW <= 0 {
+		return nil, fmt.Errorf("kernel larger than input %v", x.Shape)
+	}
+	out := &Tensor{Shape: []int{batch, filters, outH, outW}, Data: make([]float32, batch*filters*outH*outW)}
+	perGroup := filters / group
+
+	for b := 0; b < batch; b++ {
+		for f := 0; f < filters; f++ {
+			g := f / perGroup
+			var bv float32
+			if bias != nil {
+				bv = bias.Data[f]
+			}
+			for oy := 0; oy < outH; oy++ {
+				for ox := 0; ox < outW; ox++ {
+					sum := bv
+					for c := 0; c < groupChannels; c++ {
+						ic := g*groupChannels + c
+						for ky := 0; ky < kh; ky++ {
+							iy := oy*strides[0] - pads[0] + ky*dilations[0]
+							if iy < 0 || iy >= height {
+								continue
+							}
+							for kx := 0; kx < kw; kx++ {
+								ix := ox*strides[1] - pads[1] + kx*dilations[1]
+								if ix < 0 || ix >= width {
+									continue
+								}
+								sum += x.Data[((b*channels+ic)*height+iy)*width+ix] *
+									w.Data[((f*groupChannels+c)*kh+ky)*kw+kx]
+							}
+						}
+					}
+					out.Data[((b*filters+f)*outH+oy)*outW+ox] = sum
+				}
+			}
+		}
+	}
+	return []*Tensor{out}, nil
+}
+
+// pool is 2D max or average pooling over NCHW input
+func pool(takeMax bool) opFunc {
+	return func(n node, in []*Tensor) ([]*Tensor, error) {
+		x := in[0]
+		if len(x.Shape) != 4 {
+			return nil, fmt.Errorf("only 2D pooling is supported")
+		}
+		if pad := string(n.attrs["auto_pad"].s); pad != "" && pad != "NOTSET" && pad != "VALID" {
+			return nil, fmt.Errorf("auto_pad %s not supported", pad)
+		}
+		if n.intAttr("ceil_mode", 0) != 0 {
+			return nil, fmt.Errorf("ceil_mode not supported")
+		}
+		kernel := n.intsAttr("kernel_shape", 2, 1)
+		strides := n.intsAttr("strides", 2, 1)
+		pads := n.intsAttr("pads", 4, 0)
+		if err := positive("kernel_shape", kernel); err != nil {
+			return nil, err
+		}
+		if err := positive("strides", strides); err != nil {
+			return nil, err
+		}
+		if err := nonNegative("pads", pads); err != nil {
+			return nil, err
+		}
+		includePad := n.intAttr("count_include_pad", 0) != 0
+
+		batch, channels, height, width := x.Shape[0], x.Shape[1], x.Shape[2], x.Shape[3]
+		outH := (height+pads[0]+pads[2]-kernel[0])/strides[0] + 1
+		outW := (width+pads[1]+pads[3]-kernel[1])/strides[1] + 1
+		if outH <= 0 || outW <= 0 {
+			return nil, fmt.Errorf("kernel larger than input %v", x.Shape)
+		}
+		out := &Tensor{Shape: []int{batch, channels, outH, outW}, Data: make([]float32, batch*channels*outH*outW)}
+
+		for bc := 0; bc < batch*channels; bc++ {
+			plane := x.Data[bc*height*width : (bc+1)*height*width]
+			for oy := 0; oy < outH; oy++ {
+				for ox := 0; ox < outW; ox++ {
+					acc := float32(math.Inf(-1))
+					if !takeMax {
+						acc = 0
+					}
+					count := 0
+					for ky := 0; ky < kernel[0]; ky++ {
+						iy := oy*strides[0] - pads[0] + ky
+						for kx := 0; kx < kernel[1]; kx++ {
+							ix := ox*strides[1] - pads[1] + kx
+							if iy < 0 || iy >= height || ix < 0 || ix >= width {
+								if includePad {
+									count++
+								}
+								continue
+							}
+							v := plane[iy*width+ix]
+							if takeMax {
+								if v > acc {
+									acc = v
+								}
+							} else {
+								acc += v
+							}
+							count++
+						}
+					}
+					if !takeMax && count > 0 {
+						acc /= float32(count)
+					}
+					out.Data[(bc*outH+oy)*outW+ox] = acc
+				}
+			}
+		}
+		return []*Tensor{out}, nil
+	}
+}
+
+func globalAveragePool(n node, in []*Tensor) ([]*Tensor, error) {
+	x := in[0]
+	if len(x.Shape) != 4 {
+		return nil, fmt.Errorf("only 2D pooling is supported")
+	}
+	planes, area := x.Shape[0]*x.Shape[1], x.Shape[2]*x.Shape[3]
+	out := &Tensor{Shape: []int{x.Shape[0], x.Shape[1], 1, 1}, Data: make([]float32, planes)}
+	for p := 0; p < planes; p++ {
+		var sum float32
+		for _, v := range x.Data[p*area : (p+1)*area] {
+			sum += v
+		}
+		out.Data[p] = sum / float32(area)
+	}
+	return []*Tensor{out}, nil
+}
+
+// batchNorm applies inference-mode normalization per channel (dimension 1)
+func batchNorm(n node, in []*Tensor) ([]*Tensor, error) {
+	x, scale, bias, mean, variance := in[0], in[1], in[2], in[3], in[4]
+	if len(x.Shape) < 2 {
+		return nil, fmt.Errorf("input %v has no channel dimension", x.Shape)
+	}
+	eps := n.floatAttr("epsilon", 1e-5)
+	channels := x.Shape[1]
+	for i, t := range []*Tensor{scale, bias, mean, variance} {
+		if len(t.Shape) != 1 || len(t.Data) != channels {
+			return nil, fmt.Errorf("input %d shape %v does not match %d channels", i+1, t.Shape, channels)
+		}
+	}
+	inner := product(x.Shape[2:])
+	out := &Tensor{Shape: x.Shape, Data: make([]float32, len(x.Data))}
+	for i, v := range x.Data {
+		c := i / inner % channels
+		out.Data[i] = (v-mean.Data[c])/float32(math.Sqrt(float64(variance.Data[c]+eps)))*scale.Data[c] + bias.Data[c]
+	}
+	return []*Tensor{out}, nil
+}
+
+func flatten(n node, in []*Tensor) ([]*Tensor, error) {
+	x := in[0]
+	axis := int(n.intAttr("axis", 1))
+	if axis < 0 {
+		axis += len(x.Shape)
+	}
+	if axis < 0 || axis > len(x.Shape) {
+		return nil, fmt.Errorf("axis %d out of range for %v", axis, x.Shape)
+	}
+	return []*Tensor{{Shape: []int{product(x.Shape[:axis]), product(x.Shape[axis:])}, Data: x.Data}}, nil
+}
+
+func reshape(n node, in []*Tensor) ([]*Tensor, error) {
+	x, target := in[0], in[1]
+	shape := make([]int, len(target.Data))
+	infer := -1
+	known := 1
+	for i, v := range target.Data {
+		switch d := int(v); {
+		case d == 0:
+			if i >= len(x.Shape) {
+				return nil, fmt.Errorf("shape %v copies a missing dimension", target.Data)
+			}
+			shape[i] = x.Shape[i]
+		case d == -1:
+			if infer >= 0 {
+				return nil, fmt.Errorf("shape %v has more than one -1", target.Data)
+			}
+			infer = i
+			continue
+		case d < -1:
+			return nil, fmt.Errorf("shape %v has a negative dimension", target.Data)
+		default:
+			shape[i] = d
+		}
+		known *= shape[i]
+	}
+	if infer >= 0 {
+		if known == 0 || len(x.Data)%known != 0 {
+			return nil, fmt.Errorf("cannot reshape %v to %v", x.Shape, target.Data)
+		}
+		shape[infer] = len(x.Data) / known
+	}
+	if product(shape) != len(x.Data) {
+		return nil, fmt.Errorf("cannot reshape %v to %v", x.Shape, shape)
+	}
+	return []*Tensor{{Shape: shape, Data: x.Data}}, nil
+}
+
+// gemm computes alpha*A'*B' + beta*C for 2D A and B, optionally transposed
+func gemm(n node, in []*Tensor) ([]*Tensor, error) {
+	a, b := in[0], in[1]
+	if len(a.Shape) != 2 || len(b.Shape) != 2 {
+		return nil, fmt.Errorf("inputs must be 2D, got %v and %v", a.Shape, b.Shape)
+	}
+	transA, transB := n.intAttr("transA", 0) != 0, n.intAttr("transB", 0) != 0
+	alpha, beta := n.floatAttr("alpha", 1), n.floatAttr("beta", 1)
+
+	m, k := a.Shape[0], a.Shape[1]
+	if transA {
+		m, k = k, m
+	}
+	kb, cols := b.Shape[0], b.Shape[1]
+	if transB {
+		kb, cols = cols, kb
+	}
+	if k != kb {
+		return nil, fmt.Errorf("cannot multiply %v by %v", a.Shape, b.Shape)
+	}
+
+	out := &Tensor{Shape: []int{m, cols}, Data: make([]float32, m*cols)}
+	for i := 0; i < m; i++ {
+		for j := 0; j < cols; j++ {
+			var sum float32
+			for p := 0; p < k; p++ {
+				ai, bi := i*a.Shape[1]+p, p*b.Shape[1]+j
+				if transA {
+					ai = p*a.Shape[1] + i
+				}
+				if transB {
+					bi = j*b.Shape[1] + p
+				}
+				sum += a.Data[ai] * b.Data[bi]
+			}
+			out.Data[i*cols+j] = alpha * sum
+		}
+	}
+
+	if c := optional(in, 2); c != nil {
+		var err error
+		out, err = broadcast(out, c, func(x, y float32) float32 { return x + beta*y })
+		if err != nil {
+			return nil, err
+		}
+	}
+	return []*Tensor{out}, nil
+}
+
+func matMul(n node, in []*Tensor) ([]*Tensor, error) {
+	if len(in[0].Shape) != 2 || len(in[1].Shape) != 2 {
+		return nil, fmt.Errorf("only 2D MatMul is supported")
+	}
+	plain := node{attrs: map[string]attr{}}
+	return gemm(plain, in[:2])
+}
+
+// softmax normalizes along axis (default: the last)
+func softmax(n node, in []*Tensor) ([]*Tensor, error) {
+	x := in[0]
+	axis := int(n.intAttr("axis", -1))
+	if axis < 0 {
+		axis += len(x.Shape)
+	}
+	if axis < 0 || axis >= len(x.Shape) {
+		return nil, fmt.Errorf("axis %d out of range for %v", axis, x.Shape)
+	}
+	size, inner := x.Shape[axis], product(x.Shape[axis+1:])
+	out := &Tensor{Shape: x.Shape, Data: make([]float32, len(x.Data))}
+	if len(x.Data) == 0 {
+		return []*Tensor{out}, nil
+	}
+	outer := len(x.Data) / (size * inner)
+
+	for o := 0; o < outer; o++ {
+		for i := 0; i < inner; i++ {
+			at := func(k int) int { return (o*size+k)*inner + i }
+			peak := math.Inf(-1)
+			for k := 0; k < size; k++ {
+				peak = math.Max(peak, float64(x.Data[at(k)]))
+			}
+			var sum float64
+			for k := 0; k < size; k++ {
+				e := math.Exp(float64(x.Data[at(k)]) - peak)
+				out.Data[at(k)] = float32(e)
+				sum += e
+			}
+			for k := 0; k < size; k++ {
+				out.Data[at(k)] = float32(float64(out.Data[at(k)]) / sum)
+			}
+		}
+	}
+	return []*Tensor{out}, nil
+}
@@ -0,0 +1,164 @@
+package onnx
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func tensor(shape []int, data ...float32) *Tensor {
+	return NewTensor(shape, data)
+}
+
+func TestOps(t *testing.T) {
+	x22 := tensor([]int{2, 2}, 1, -2, 3, -4)
+	img := tensor([]int{1, 1, 3, 3}, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+
+	tests := []struct {
+		name    string
+		op      string
+		attrs   map[string]attr
+		in      []*Tensor
+		want    *Tensor
+		wantErr string
+	}{
+		{name: "identity", op: "Identity", in: []*Tensor{x22}, want: x22},
+		{name: "dropout ignores ratio", op: "Dropout", in: []*Tensor{x22, tensor(nil, 0.5)}, want: x22},
+		{name: "identity without input", op: "Identity", in: nil, wantErr: "takes 1 inputs, got 0"},
+		{name: "identity nil input", op: "Identity", in: []*Tensor{nil}, wantErr: "required input 0 missing"},
+		{name: "constant", op: "Constant", attrs: map[string]attr{"value": {t: x22}}, want: x22},
+		{name: "constant without value", op: "Constant", wantErr: "only tensor constants"},
+
+		{name: "relu", op: "Relu", in: []*Tensor{x22}, want: tensor([]int{2, 2}, 1, 0, 3, 0)},
+		{name: "leaky relu", op: "LeakyRelu", attrs: map[string]attr{"alpha": {f: 0.5}}, in: []*Tensor{x22}, want: tensor([]int{2, 2}, 1, -1, 3, -2)},
+		{name: "sigmoid", op: "Sigmoid", in: []*Tensor{tensor([]int{1}, 0)}, want: tensor([]int{1}, 0.5)},
+		{name: "tanh", op: "Tanh", in: []*Tensor{tensor([]int{1}, 0)}, want: tensor([]int{1}, 0)},
+
+		{name: "add broadcast row", op: "Add", in: []*Tensor{x22, tensor([]int{2}, 10, 20)}, want: tensor([]int{2, 2}, 11, 18, 13, 16)},
+		{name: "mul broadcast scalar", op: "Mul", in: []*Tensor{x22, tensor(nil, 2)}, want: tensor([]int{2, 2}, 2, -4, 6, -8)},
+		{name: "sub broadcast column", op: "Sub", in: []*Tensor{x22, tensor([]int{2, 1}, 1, 2)}, want: tensor([]int{2, 2}, 0, -3, 1, -6)},
+		{name: "div", op: "Div", in: []*Tensor{x22, tensor([]int{2, 2}, 1, 2, 3, 4)}, want: tensor([]int{2, 2}, 1, -1, 1, -1)},
+		{name: "add missing operand", op: "Add", in: []*Tensor{x22}, wantErr: "takes 2 inputs, got 1"},
+		{name: "add nil operand", op: "Add", in: []*Tensor{x22, nil}, wantErr: "required input 1 missing"},
+		{name: "add incompatible", op: "Add", in: []*Tensor{x22, tensor([]int{3}, 1, 2, 3)}, wantErr: "cannot broadcast"},
+
+		{
+			name: "conv with bias",
+			op:   "Conv",
+			in:   []*Tensor{img, tensor([]int{1, 1, 2, 2}, 1, 0, 0, 1), tensor([]int{1}, 100)},
+			want: tensor([]int{1, 1, 2, 2}, 106, 108, 112, 114),
+		},
+		{
+			name: "conv padded and strided",
+			op:   "Conv",
+			attrs: map[string]attr{
+				"pads":    {ints: []int64{1, 1, 1, 1}},
+				"strides": {ints: []int64{2, 2}},
+			},
+			in:   []*Tensor{img, tensor([]int{1, 1, 1, 1}, 2)},
+			want: tensor([]int{1, 1, 3, 3}, 0, 0, 0, 0, 10, 0, 0, 0, 0),
+		},
+		{name: "conv omitted bias", op: "Conv", in: []*Tensor{img, tensor([]int{1, 1, 1, 1}, 1), nil}, want: img},
+		{name: "conv bias too short", op: "Conv", in: []*Tensor{img, tensor([]int{2, 1, 1, 1}, 1, 1), tensor([]int{1}, 0)}, wantErr: "does not match 2 filters"},
+		{name: "conv channel mismatch", op: "Conv", in: []*Tensor{img, tensor([]int{1, 2, 1, 1}, 1, 1)}, wantErr: "does not match weights"},
+		{name: "conv zero group", op: "Conv", attrs: map[string]attr{"group": {i: 0}}, in: []*Tensor{img, tensor([]int{1, 1, 1, 1}, 1)}, wantErr: "with group 0"},
+		{name: "conv zero stride", op: "Conv", attrs: map[string]attr{"strides": {ints: []int64{0, 1}}}, in: []*Tensor{img, tensor([]int{1, 1, 1, 1}, 1)}, wantErr: "strides"},
+		{name: "conv negative pad", op: "Conv", attrs: map[string]attr{"pads": {ints: []int64{-1, 0, 0, 0}}}, in: []*Tensor{img, tensor([]int{1, 1, 1, 1}, 1)}, wantErr: "pads"},
+		{name: "conv 1D", op: "Conv", in: []*Tensor{x22, x22}, wantErr: "only 2D"},
+
+		{name: "max pool", op: "MaxPool", attrs: map[string]attr{"kernel_shape": {ints: []int64{2, 2}}}, in: []*Tensor{img}, want: tensor([]int{1, 1, 2, 2}, 5, 6, 8, 9)},
+		{
+			name:  "average pool",
+			op:    "AveragePool",
+			attrs: map[string]attr{"kernel_shape": {ints: []int64{2, 2}}, "strides": {ints: []int64{1, 1}}},
+			in:    []*Tensor{img},
+			want:  tensor([]int{1, 1, 2, 2}, 3, 4, 6, 7),
+		},
+		{name: "pool zero kernel", op: "MaxPool", attrs: map[string]attr{"kernel_shape": {ints: []int64{0, 2}}}, in: []*Tensor{img}, wantErr: "kernel_shape"},
+		{name: "pool kernel too large", op: "MaxPool", attrs: map[string]attr{"kernel_shape": {ints: []int64{4, 4}}}, in: []*Tensor{img}, wantErr: "kernel larger"},
+		{name: "global average pool", op: "GlobalAveragePool", in: []*Tensor{img}, want: tensor([]int{1, 1, 1, 1}, 5)},
+
+		{
+			name: "batch norm",
+			op:   "BatchNormalization",
+			in: []*Tensor{
+				tensor([]int{1, 2, 1, 1}, 3, 5),
+				tensor([]int{2}, 2, 1), tensor([]int{2}, 1, 0), tensor([]int{2}, 1, 5), tensor([]int{2}, 4, 1),
+			},
+			attrs: map[string]attr{"epsilon": {f: 0}},
+			want:  tensor([]int{1, 2, 1, 1}, 3, 0),
+		},
+		{name: "batch norm missing stats", op: "BatchNormalization", in: []*Tensor{img, tensor([]int{1}, 1)}, wantErr: "takes 5 inputs, got 2"},
+		{
+			name:    "batch norm short stats",
+			op:      "BatchNormalization",
+			in:      []*Tensor{tensor([]int{1, 2}, 1, 2), tensor([]int{2}, 1, 1), tensor([]int{2}, 0, 0), tensor([]int{1}, 0), tensor([]int{2}, 1, 1)},
+			wantErr: "input 3 shape [1] does not match 2 channels",
+		},
+
+		{name: "flatten", op: "Flatten", in: []*Tensor{img}, want: tensor([]int{1, 9}, 1, 2, 3, 4, 5, 6, 7, 8, 9)},
+		{name: "flatten bad axis", op: "Flatten", attrs: map[string]attr{"axis": {i: 5}}, in: []*Tensor{img}, wantErr: "out of range"},
+		{name: "reshape infers", op: "Reshape", in: []*Tensor{img, tensor([]int{2}, 0, -1)}, want: tensor([]int{1, 9}, 1, 2, 3, 4, 5, 6, 7, 8, 9)},
+		{name: "reshape without shape", op: "Reshape", in: []*Tensor{img}, wantErr: "takes 2 inputs, got 1"},
+		{name: "reshape negative", op: "Reshape", in: []*Tensor{x22, tensor([]int{2}, -2, -2)}, wantErr: "negative dimension"},
+		{name: "reshape wrong size", op: "Reshape", in: []*Tensor{x22, tensor([]int{1}, 3)}, wantErr: "cannot reshape"},
+
+		{
+			name:  "gemm transposed with bias",
+			op:    "Gemm",
+			attrs: map[string]attr{"transB": {i: 1}, "beta": {f: 2}},
+			in:    []*Tensor{tensor([]int{1, 2}, 1, 2), tensor([]int{3, 2}, 1, 0, 0, 1, 1, 1), tensor([]int{3}, 1, 1, 1)},
+			want:  tensor([]int{1, 3}, 3, 4, 5),
+		},
+		{name: "gemm mismatch", op: "Gemm", in: []*Tensor{x22, tensor([]int{3, 1}, 1, 2, 3)}, wantErr: "cannot multiply"},
+		{name: "matmul", op: "MatMul", in: []*Tensor{x22, tensor([]int{2, 1}, 1, 1)}, want: tensor([]int{2, 1}, -1, -1)},
+		{name: "matmul 3D", op: "MatMul", in: []*Tensor{img, x22}, wantErr: "only 2D"},
+
+		{name: "softmax", op: "Softmax", in: []*Tensor{tensor([]int{1, 2}, 0, 0)}, want: tensor([]int{1, 2}, 0.5, 0.5)},
+		{name: "softmax empty", op: "Softmax", in: []*Tensor{tensor([]int{0, 2})}, want: tensor([]int{0, 2})},
+		{name: "softmax bad axis", op: "Softmax", attrs: map[string]attr{"axis": {i: 2}}, in: []*Tensor{x22}, wantErr: "out of range"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			n := node{op: tc.op, attrs: tc.attrs}
+			if n.attrs == nil {
+				n.attrs = map[string]attr{}
+			}
+			out, err := ops[tc.op].apply(n, tc.in)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("got error %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(out) != 1 {
+				t.Fatalf("got %d outputs, want 1", len(out))
+			}
+			assertTensor(t, out[0], tc.want)
+		})
+	}
+}
+
+func assertTensor(t *testing.T, got, want *Tensor) {
+	t.Helper()
+	if len(got.Shape) != len(want.Shape) || product(got.Shape) != product(want.Shape) {
+		t.Fatalf("shape %v, want %v", got.Shape, want.Shape)
+	}
+	for i, d := range want.Shape {
+		if got.Shape[i] != d {
+			t.Fatalf("shape %v, want %v", got.Shape, want.Shape)
+		}
+	}
+	if len(got.Data) != len(want.Data) {
+		t.Fatalf("%d values, want %d", len(got.Data), len(want.Data))
+	}
+	for i := range want.Data {
+		if math.Abs(float64(got.Data[i]-want.Data[i])) > 1e-5 {
+			t.Fatalf("data %v, want %v", got.Data, want.Data)
+		}
+	}
+}
@@ -0,0 +1,216 @@
+package store
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	"github.com/yourorg/proptoken-oracle/pkg/types"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Indexer buckets: the asset table keyed by fingerprint hex, a per-block undo
+// journal keyed by big-endian block number, and the indexer's cursor.
+var (
+	assetsBucket  = []byte("assets")
+	journalBucket = []byte("asset_journal")
+	cursorBucket  = []byte("indexer")
+	cursorKey     = []byte("cursor")
+)
+
+// BlockChanges is the indexed state of every asset touched in one block
+type BlockChanges struct {
+	Number uint64
+	Hash   string
+	Assets []types.IndexedAsset
+}
+
+// IndexCursor is the last block the indexer has fully applied
+type IndexCursor struct {
+	Number uint64 `json:"number"`
+	Hash   string `json:"hash"` // empty if unknown, e.g. after rolling back past the journal
+}
+
+// journalEntry records what a block overwrote, so it can be undone on a reorg
+type journalEntry struct {
+	Hash string                         `json:"hash"`
+	Prev map[string]*types.IndexedAsset `json:"prev"` // nil value: asset did not exist
+}
+
+func (s *Store) initAssetBuckets(tx *bolt.Tx) error {
+	for _, name := range [][]byte{assetsBucket, journalBucket, cursorBucket} {
+		if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetAsset returns the indexed asset for a 0x-prefixed fingerprint
+func (s *Store) GetAsset(fingerprint string) (*types.IndexedAsset, error) {
+	var asset *types.IndexedAsset
+	err := s.db.View(func(tx *bolt.Tx) error {
+		raw := tx.Bucket(assetsBucket).Get([]byte(fingerprint))
+		if raw == nil {
+			return ErrNotFound
+		}
+		asset = &types.IndexedAsset{}
+		if err := json.Unmarshal(raw, asset); err != nil {
+			return fmt.Errorf("corrupt asset record: %v", err)
+		}
+		return nil
+	})
+	return asset, err
+}
+
+// Cursor returns the indexer position, or ok=false if nothing has been indexed yet
+func (s *Store) Cursor() (cursor IndexCursor, ok bool, err error) {
+	err = s.db.View(func(tx *bolt.Tx) error {
+		raw := tx.Bucket(cursorBucket).Get(cursorKey)
+		if raw == nil {
+			return nil
+		}
+		ok = true
+		return json.Unmarshal(raw, &cursor)
+	})
+	return cursor, ok, err
+}
+
+// ApplyBlocks writes the changes of consecutive blocks and advances the cursor to
+// `to`, atomically. Each block is journaled so RollbackTo can undo it.
+func (s *Store) ApplyBlocks(blocks []BlockChanges, to IndexCursor) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		assets := tx.Bucket(assetsBucket)
+		journal := tx.Bucket(journalBucket)
+
+		for _, block := range blocks {
+			entry := journalEntry{Hash: block.Hash, Prev: make(map[string]*types.IndexedAsset)}
+			for _, asset := range block.Assets {
+				if _, seen := entry.Prev[asset.Fingerprint]; !seen {
+					prev, err := getAsset(assets, asset.Fingerprint)
+					if err != nil {
+						return err
+					}
+					entry.Prev[asset.Fingerprint] = prev
+				}
+				raw, err := json.Marshal(asset)
+				if err != nil {
+					return err
+				}
+				if err := assets.Put([]byte(asset.Fingerprint), raw); err != nil {
+					return err
+				}
+			}
+			if err := putJSON(journal, blockKey(block.Number), entry); err != nil {
+				return err
+			}
+		}
+
+		// Checkpoint the cursor block so reorgs past it can be detected
+		if journal.Get(blockKey(to.Number)) == nil {
+			entry := journalEntry{Hash: to.Hash, Prev: map[string]*types.IndexedAsset{}}
+			if err := putJSON(journal, blockKey(to.Number), entry); err != nil {
+				return err
+			}
+		}
+		return putJSON(tx.Bucket(cursorBucket), cursorKey, to)
+	})
+}
+
+// JournalHashes returns the recorded block hashes, newest first
+func (s *Store) JournalHashes() ([]IndexCursor, error) {
+	var blocks []IndexCursor
+	err := s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(journalBucket).Cursor()
+		for k, v := c.Last(); k != nil; k, v = c.Prev() {
+			var entry journalEntry
+			if err := json.Unmarshal(v, &entry); err != nil {
+				return err
+			}
+			blocks = append(blocks, IndexCursor{Number: binary.BigEndian.Uint64(k), Hash: entry.Hash})
+		}
+		return nil
+	})
+	return blocks, err
+}
+
+// RollbackTo undoes every journaled block after `to` (newest first) and moves the cursor back
+func (s *Store) RollbackTo(to IndexCursor) error {
+	return s.rollback(&to)
+}
+
+// RollbackAll undoes every journaled block and clears the cursor, so indexing
+// starts over from the configured start block
+func (s *Store) RollbackAll() error {
+	return s.rollback(nil)
+}
+
+func (s *Store) rollback(to *IndexCursor) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		assets := tx.Bucket(assetsBucket)
+		journal := tx.Bucket(journalBucket)
+
+		c := journal.Cursor()
+		for k, v := c.Last(); k != nil && (to == nil || binary.BigEndian.Uint64(k) > to.Number); k, v = c.Last() {
+			var entry journalEntry
+			if err := json.Unmarshal(v, &entry); err != nil {
+				return err
+			}
+			for fingerprint, prev := range entry.Prev {
+				if prev == nil {
+					if err := assets.Delete([]byte(fingerprint)); err != nil {
+						return err
+					}
+					continue
+				}
+				if err := putJSON(assets, []byte(fingerprint), prev); err != nil {
+					return err
+				}
+			}
+			if err := journal.Delete(k); err != nil {
+				return err
+			}
+		}
+		if to == nil {
+			return tx.Bucket(cursorBucket).Delete(cursorKey)
+		}
+		return putJSON(tx.Bucket(cursorBucket), cursorKey, to)
+	})
+}
+
+// PruneJournal drops undo entries below `below`; those blocks are considered final
+func (s *Store) PruneJournal(below uint64) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		c := tx.Bucket(journalBucket).Cursor()
+		for k, _ := c.First(); k != nil && binary.BigEndian.Uint64(k) < below; k, _ = c.First() {
+			if err := c.Delete(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func getAsset(b *bolt.Bucket, fingerprint string) (*types.IndexedAsset, error) {
+	raw := b.Get([]byte(fingerprint))
+	if raw == nil {
+		return nil, nil
+	}
+	var asset types.IndexedAsset
+	if err := json.Unmarshal(raw, &asset); err != nil {
+		return nil, fmt.Errorf("corrupt asset record: %v", err)
+	}
+	return &asset, nil
+}
+
+func putJSON(b *bolt.Bucket, key []byte, v interface{}) error {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return b.Put(key, raw)
+}
+
+func blockKey(n uint64) []byte {
+	return versionKey(n)
+}
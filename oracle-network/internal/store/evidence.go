@@ -0,0 +1,40 @@
+package store
+
+import (
+	"github.com/ethereum/go-ethereum/crypto"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Raw provider evidence, keyed by the 0x-prefixed keccak256 hex of its bytes
+var evidenceBucket = []byte("evidence")
+
+// PutEvidence stores data under its keccak256 hash and returns the hash.
+// Storing the same bytes again is a no-op.
+func (s *Store) PutEvidence(data []byte) (string, error) {
+	hash := crypto.Keccak256Hash(data).Hex()
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(evidenceBucket)
+		if b.Get([]byte(hash)) != nil {
+			return nil
+		}
+		return b.Put([]byte(hash), data)
+	})
+	if err != nil {
+		return "", err
+	}
+	return hash, nil
+}
+
+// GetEvidence returns the bytes stored under a 0x-prefixed keccak256 hash
+func (s *Store) GetEvidence(hash string) ([]byte, error) {
+	var data []byte
+	err := s.db.View(func(tx *bolt.Tx) error {
+		raw := tx.Bucket(evidenceBucket).Get([]byte(hash))
+		if raw == nil {
+			return ErrNotFound
+		}
+		data = append([]byte(nil), raw...)
+		return nil
+	})
+	return data, err
+}
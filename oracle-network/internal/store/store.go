@@ -0,0 +1,225 @@
+package store
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/yourorg/proptoken-oracle/pkg/types"
+	bolt "go.etcd.io/bbolt"
+)
+
+var ErrNotFound = errors.New("not found")
+
+// Top-level bucket; holds one nested bucket per submission ID,
+// keyed by big-endian version number.
+var verificationsBucket = []byte("verifications")
+
+// Store persists verification results in a local BoltDB file
+type Store struct {
+	db *bolt.DB
+}
+
+// ListFilter narrows ListVerifications. Zero values match everything.
+type ListFilter struct {
+	Status string
+	Since  time.Time
+}
+
+func Open(path string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create store directory: %v", err)
+	}
+
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open store %s: %v", path, err)
+	}
+
+	s := &Store{db: db}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{verificationsBucket, evidenceBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return s.initAssetBuckets(tx)
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to init store: %v", err)
+	}
+
+	return s, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// SaveVerification appends rec as the next version for its submission
+// and sets rec.Version accordingly.
+func (s *Store) SaveVerification(rec *types.VerificationRecord) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.Bucket(verificationsBucket).CreateBucketIfNotExists([]byte(rec.SubmissionID))
+		if err != nil {
+			return err
+		}
+
+		seq, err := b.NextSequence()
+		if err != nil {
+			return err
+		}
+		rec.Version = int(seq)
+
+		raw, err := json.Marshal(rec)
+		if err != nil {
+			return err
+		}
+		return b.Put(versionKey(seq), raw)
+	})
+}
+
+// TxUpdate is the chain outcome recorded against a stored version
+type TxUpdate struct {
+	Hash   string
+	Status string
+	Block  uint64
+	Error  string
+}
+
+// UpdateTxStatus sets the chain status of one stored version
+func (s *Store) UpdateTxStatus(submissionID string, version int, update TxUpdate) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(verificationsBucket).Bucket([]byte(submissionID))
+		if b == nil {
+			return ErrNotFound
+		}
+		key := versionKey(uint64(version))
+		raw := b.Get(key)
+		if raw == nil {
+			return ErrNotFound
+		}
+
+		var rec types.VerificationRecord
+		if err := json.Unmarshal(raw, &rec); err != nil {
+			return err
+		}
+		rec.TxHash = update.Hash
+		rec.TxStatus = update.Status
+		rec.TxBlock = update.Block
+		rec.TxError = update.Error
+		rec.Result.Attestation.TxHash = update.Hash
+		rec.Result.Attestation.TxStatus = update.Status
+
+		updated, err := json.Marshal(&rec)
+		if err != nil {
+			return err
+		}
+		return b.Put(key, updated)
+	})
+}
+
+// GetVerification returns the latest version for a submission
+func (s *Store) GetVerification(submissionID string) (*types.VerificationRecord, error) {
+	var rec *types.VerificationRecord
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(verificationsBucket).Bucket([]byte(submissionID))
+		if b == nil {
+			return ErrNotFound
+		}
+		_, raw := b.Cursor().Last()
+		if raw == nil {
+			return ErrNotFound
+		}
+
+		var err error
+		rec, err = decode(raw)
+		return err
+	})
+	return rec, err
+}
+
+// History returns every version for a submission, oldest first
+func (s *Store) History(submissionID string) ([]types.VerificationRecord, error) {
+	var history []types.VerificationRecord
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(verificationsBucket).Bucket([]byte(submissionID))
+		if b == nil {
+			return ErrNotFound
+		}
+		return b.ForEach(func(_, raw []byte) error {
+			rec, err := decode(raw)
+			if err != nil {
+				return err
+			}
+			history = append(history, *rec)
+			return nil
+		})
+	})
+	return history, err
+}
+
+// ListVerifications returns the latest version of each submission that
+// matches filter, newest first.
+func (s *Store) ListVerifications(filter ListFilter) ([]types.VerificationRecord, error) {
+	list := []types.VerificationRecord{}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		root := tx.Bucket(verificationsBucket)
+		return root.ForEachBucket(func(id []byte) error {
+			_, raw := root.Bucket(id).Cursor().Last()
+			if raw == nil {
+				return nil
+			}
+			rec, err := decode(raw)
+			if err != nil {
+				return err
+			}
+
+			if filter.Status != "" && rec.Status != filter.Status {
+				return nil
+			}
+			if !filter.Since.IsZero() && rec.CreatedAt.Before(filter.Since) {
+				return nil
+			}
+			list = append(list, *rec)
+			return nil
+		})
+	})
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].CreatedAt.After(list[j].CreatedAt)
+	})
+	return list, err
+}
+
+// SubmissionIDs returns the IDs of every stored submission
+func (s *Store) SubmissionIDs() ([]string, error) {
+	var ids []string
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(verificationsBucket).ForEachBucket(func(id []byte) error {
+			ids = append(ids, string(id))
+			return nil
+		})
+	})
+	return ids, err
+}
+
+func versionKey(v uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, v)
+	return key
+}
+
+func decode(raw []byte) (*types.VerificationRecord, error) {
+	var rec types.VerificationRecord
+	if err := json.Unmarshal(raw, &rec); err != nil {
+		return nil, fmt.Errorf("corrupt verification record: %v", err)
+	}
+	return &rec, nil
+}
@@ -0,0 +1,184 @@
+package store
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/yourorg/proptoken-oracle/pkg/types"
+)
+
+func openTestStore(t *testing.T, path string) *Store {
+	t.Helper()
+	s, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func record(id, status, root string, created time.Time) *types.VerificationRecord {
+	return &types.VerificationRecord{
+		SubmissionID: id,
+		Status:       status,
+		Result: types.OracleResult{
+			SubmissionID: id,
+			Attestation:  types.AttestationData{MerkleRoot: root},
+		},
+		CreatedAt: created,
+	}
+}
+
+func TestVerificationRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "oracle.db")
+	s := openTestStore(t, path)
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for i, root := range []string{"0x01", "0x02"} {
+		rec := record("sub-1", types.VerificationPassed, root, created.Add(time.Duration(i)*time.Hour))
+		if err := s.SaveVerification(rec); err != nil {
+			t.Fatal(err)
+		}
+		if rec.Version != i+1 {
+			t.Errorf("saved as version %d, want %d", rec.Version, i+1)
+		}
+	}
+	if err := s.UpdateTxStatus("sub-1", 1, TxUpdate{Hash: "0xabc", Status: types.TxConfirmed, Block: 7}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Everything survives a reopen
+	s.Close()
+	s = openTestStore(t, path)
+
+	latest, err := s.GetVerification("sub-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest.Version != 2 || latest.Result.Attestation.MerkleRoot != "0x02" || !latest.CreatedAt.Equal(created.Add(time.Hour)) {
+		t.Errorf("latest %+v, want version 2", latest)
+	}
+
+	history, err := s.History("sub-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 2 || history[0].Version != 1 || history[1].Version != 2 {
+		t.Fatalf("history %+v, want versions 1 and 2 oldest first", history)
+	}
+	first := history[0]
+	if first.TxHash != "0xabc" || first.TxStatus != types.TxConfirmed || first.TxBlock != 7 {
+		t.Errorf("version 1 tx %s %s at %d", first.TxHash, first.TxStatus, first.TxBlock)
+	}
+	if first.Result.Attestation.TxHash != "0xabc" || first.Result.Attestation.TxStatus != types.TxConfirmed {
+		t.Errorf("version 1 result tx %+v", first.Result.Attestation)
+	}
+	if history[1].TxHash != "" {
+		t.Errorf("update leaked into version 2: %+v", history[1])
+	}
+}
+
+func TestVerificationNotFound(t *testing.T) {
+	s := openTestStore(t, filepath.Join(t.TempDir(), "oracle.db"))
+	if err := s.SaveVerification(record("sub-1", types.VerificationPassed, "0x01", time.Now())); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"get", func() error { _, err := s.GetVerification("sub-2"); return err }()},
+		{"history", func() error { _, err := s.History("sub-2"); return err }()},
+		{"update unknown submission", s.UpdateTxStatus("sub-2", 1, TxUpdate{})},
+		{"update unknown version", s.UpdateTxStatus("sub-1", 2, TxUpdate{})},
+		{"evidence", func() error { _, err := s.GetEvidence("0x1234"); return err }()},
+	}
+	for _, tc := range tests {
+		if !errors.Is(tc.err, ErrNotFound) {
+			t.Errorf("%s: got %v, want ErrNotFound", tc.name, tc.err)
+		}
+	}
+}
+
+func TestListVerifications(t *testing.T) {
+	s := openTestStore(t, filepath.Join(t.TempDir(), "oracle.db"))
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, rec := range []*types.VerificationRecord{
+		record("sub-a", types.VerificationPassed, "0x01", base),
+		record("sub-b", types.VerificationFailed, "0x02", base.Add(time.Hour)),
+		record("sub-c", types.VerificationPassed, "0x03", base.Add(2*time.Hour)),
+		// A newer failed version replaces sub-a's passed one in listings
+		record("sub-a", types.VerificationFailed, "0x04", base.Add(3*time.Hour)),
+	} {
+		if err := s.SaveVerification(rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		filter ListFilter
+		want   []string
+	}{
+		{"all, newest first", ListFilter{}, []string{"sub-a", "sub-c", "sub-b"}},
+		{"status", ListFilter{Status: types.VerificationPassed}, []string{"sub-c"}},
+		{"since", ListFilter{Since: base.Add(90 * time.Minute)}, []string{"sub-a", "sub-c"}},
+		{"nothing", ListFilter{Status: "unknown"}, []string{}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			list, err := s.ListVerifications(tc.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := []string{}
+			for _, rec := range list {
+				got = append(got, rec.SubmissionID)
+			}
+			if len(got) != len(tc.want) {
+				t.Fatalf("got %v, want %v", got, tc.want)
+			}
+			for i := range got {
+				if got[i] != tc.want[i] {
+					t.Fatalf("got %v, want %v", got, tc.want)
+				}
+			}
+		})
+	}
+
+	ids, err := s.SubmissionIDs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(ids)
+	if len(ids) != 3 || ids[0] != "sub-a" || ids[1] != "sub-b" || ids[2] != "sub-c" {
+		t.Errorf("submission IDs %v", ids)
+	}
+}
+
+func TestEvidenceRoundTrip(t *testing.T) {
+	s := openTestStore(t, filepath.Join(t.TempDir(), "oracle.db"))
+	data := []byte(`{"tile":"z18/1/2"}`)
+
+	hash, err := s.PutEvidence(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := s.PutEvidence(data)
+	if err != nil || again != hash {
+		t.Errorf("second put returned %s (%v), want %s", again, err, hash)
+	}
+
+	got, err := s.GetEvidence(hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("got %q, want %q", got, data)
+	}
+}
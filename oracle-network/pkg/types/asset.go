@@ -0,0 +1,39 @@
+package types
+
+import "time"
+
+// Registry asset as indexed from AssetRegistry events.
+// Scores are the raw on-chain values (1e18 fixed point; risk 0-100) as decimal strings.
+type IndexedAsset struct {
+	Fingerprint       string    `json:"fingerprint"`
+	Owner             string    `json:"owner"`
+	OracleAttestation string    `json:"oracle_attestation"`
+	ABMOutputHash     string    `json:"abm_output_hash"`
+	ExistenceScore    string    `json:"existence_score,omitempty"`
+	OwnershipScore    string    `json:"ownership_score,omitempty"`
+	FraudScore        string    `json:"fraud_score,omitempty"`
+	RiskScore         string    `json:"risk_score,omitempty"`
+	Eligible          bool      `json:"eligible"`
+	IsMock            bool      `json:"is_mock"`
+	Tokenized         bool      `json:"tokenized"`
+	TokenAddress      string    `json:"token_address,omitempty"`
+	RegisteredAt      time.Time `json:"registered_at"`
+	RegisteredBlock   uint64    `json:"registered_block"`
+	UpdatedBlock      uint64    `json:"updated_block"`
+	LastTxHash        string    `json:"last_tx_hash"`
+}
+
+// Result of checking an on-chain attestation against the locally stored evidence
+type AttestationCheck struct {
+	Fingerprint    string `json:"fingerprint"`
+	SubmissionID   string `json:"submission_id,omitempty"`
+	Version        int    `json:"version,omitempty"` // stored verification version the check used
+	OnChainRoot    string `json:"onchain_root"`
+	RecomputedRoot string `json:"recomputed_root,omitempty"`
+	RootMatches    bool   `json:"root_matches"`
+	Signer         string `json:"signer,omitempty"`
+	SignerHasRole  bool   `json:"signer_has_oracle_role"`
+	Expired        bool   `json:"expired"` // past the attestation's signed expiry
+	Valid          bool   `json:"valid"`
+	Error          string `json:"error,omitempty"` // why the check could not complete
+}
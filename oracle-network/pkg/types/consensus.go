@@ -0,0 +1,21 @@
+package types
+
+// Outcome of a multi-node consensus round, attached to the merged result
+type ConsensusData struct {
+	Threshold     int        `json:"threshold"` // M in M-of-N
+	Nodes         int        `json:"nodes"`     // N, including the coordinating node
+	AgreedRoot    string     `json:"agreed_root"`
+	Agreeing      []NodeVote `json:"agreeing"`
+	Disagreements []NodeVote `json:"disagreements,omitempty"`
+}
+
+// One node's answer in a consensus round. Agreeing votes keep the node's own
+// signature so the threshold can be checked without trusting the coordinator.
+type NodeVote struct {
+	Address    string `json:"address"`
+	URL        string `json:"url,omitempty"` // empty for the coordinating node
+	MerkleRoot string `json:"merkle_root,omitempty"`
+	Digest     string `json:"digest,omitempty"`
+	Signature  string `json:"signature,omitempty"`
+	Error      string `json:"error,omitempty"` // why the node's result could not be used
+}
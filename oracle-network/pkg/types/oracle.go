@@ -9,6 +9,8 @@ type SubmissionData struct {
 	SPV        SPVData       `json:"spv"`
 	Documents  DocumentData  `json:"documents"`
 	Financials FinancialData `json:"financials"`
+	Owner      string        `json:"owner"` // wallet address the asset is registered to
+	ABM        ABMData       `json:"abm"`
 	IsMock     bool          `json:"is_mock"`
 }
 
@@ -37,6 +39,13 @@ type FinancialData struct {
 	Valuation float64 `json:"valuation"`
 }
 
+// Output of the backend's agent-based model run, committed on chain alongside the attestation
+type ABMData struct {
+	OutputHash string  `json:"output_hash"` // 0x-prefixed 32-byte hash
+	FraudScore float64 `json:"fraud_score"` // 0-1
+	RiskScore  float64 `json:"risk_score"`  // 0-1
+}
+
 // Oracle results
 type OracleResult struct {
 	SubmissionID string          `json:"submission_id"`
@@ -44,9 +53,18 @@ type OracleResult struct {
 	Ownership    OwnershipResult `json:"ownership"`
 	Activity     ActivityResult  `json:"activity"`
 	Attestation  AttestationData `json:"attestation"`
+	Consensus    *ConsensusData  `json:"consensus,omitempty"` // set when merged from several nodes
+	Inputs       RunInputs       `json:"inputs"`
 	Timestamp    time.Time       `json:"timestamp"`
 }
 
+// RunInputs are the clock reading a verification ran with. Given the same
+// provider responses, re-running with them reproduces the signals and Merkle
+// root exactly.
+type RunInputs struct {
+	ObservedAt time.Time `json:"observed_at"` // whole seconds
+}
+
 type ExistenceResult struct {
 	Score      float64               `json:"score"`
 	Confidence float64               `json:"confidence"`
@@ -67,15 +85,84 @@ type ActivityResult struct {
 }
 
 type SignalData struct {
-	Source    string      `json:"source"`
-	Score     float64     `json:"score"`
-	Data      interface{} `json:"data"`
-	Timestamp time.Time   `json:"timestamp"`
+	Source       string       `json:"source"`
+	Score        float64      `json:"score"`
+	Data         interface{}  `json:"data"`
+	EvidenceHash string       `json:"evidence_hash"` // keccak256 of Evidence; committed in the Merkle leaf
+	Evidence     []byte       `json:"-"`             // raw bytes the signal was derived from; canonical JSON of Data if unset
+	Status       SignalStatus `json:"status"`
+	Error        string       `json:"error,omitempty"`
+	LatencyMs    int64        `json:"latency_ms"`
+	Timestamp    time.Time    `json:"timestamp"`
 }
 
+// Outcome of a provider call
+type SignalStatus string
+
+const (
+	SignalOK       SignalStatus = "ok"
+	SignalDegraded SignalStatus = "degraded" // provider overran its deadline
+	SignalFailed   SignalStatus = "failed"   // provider returned an error
+)
+
+// AttestationData carries everything needed to check the signature offline:
+// rebuild the EIP-712 struct from the result, hash it under Domain, compare with
+// Digest and recover OracleAddress from Signature.
 type AttestationData struct {
-	MerkleRoot    string    `json:"merkle_root"`
-	OracleAddress string    `json:"oracle_address"`
-	Signature     string    `json:"signature"`
-	Timestamp     time.Time `json:"timestamp"`
+	MerkleRoot    string                 `json:"merkle_root"`
+	Proofs        map[string]MerkleProof `json:"proofs"`
+	OracleAddress string                 `json:"oracle_address"`
+	Signature     string                 `json:"signature"`
+	Digest        string                 `json:"digest"` // hash that was signed
+	Scheme        string                 `json:"scheme"` // signature scheme, e.g. "eip712"
+	Domain        SigningDomain          `json:"domain"`
+	Timestamp     time.Time              `json:"timestamp"`
+	Expiry        time.Time              `json:"expiry"`
+
+	// Registry push; TxStatus is as of sending, the stored record tracks it to finality
+	TxHash   string `json:"tx_hash,omitempty"`
+	TxStatus string `json:"tx_status,omitempty"`
 }
+
+// EIP-712 domain the attestation was signed under
+type SigningDomain struct {
+	Name              string `json:"name"`
+	Version           string `json:"version"`
+	ChainID           string `json:"chain_id"`
+	VerifyingContract string `json:"verifying_contract"` // registry contract address
+}
+
+// Inclusion proof for a single signal leaf, keyed by "<category>:<signal>"
+type MerkleProof struct {
+	Leaf     string   `json:"leaf"`
+	LeafHash string   `json:"leaf_hash"`
+	Proof    []string `json:"proof"`
+}
+
+// Persisted outcome of one verification run. Re-verifying a submission
+// appends a new version rather than overwriting the previous one.
+type VerificationRecord struct {
+	SubmissionID string       `json:"submission_id"`
+	Version      int          `json:"version"`
+	Status       string       `json:"status"` // "passed" or "failed"
+	Result       OracleResult `json:"result"`
+	TxHash       string       `json:"tx_hash,omitempty"`
+	TxStatus     string       `json:"tx_status,omitempty"`
+	TxBlock      uint64       `json:"tx_block,omitempty"`
+	TxError      string       `json:"tx_error,omitempty"` // push error or decoded revert reason
+	CreatedAt    time.Time    `json:"created_at"`
+}
+
+const (
+	VerificationPassed = "passed"
+	VerificationFailed = "failed"
+)
+
+// Chain status of the attestation transaction
+const (
+	TxPending   = "pending"   // sent, awaiting confirmations
+	TxConfirmed = "confirmed" // mined successfully with enough confirmations
+	TxReverted  = "reverted"  // mined but reverted; see TxError
+	TxDropped   = "dropped"   // never mined, or its nonce was taken by another tx
+	TxFailed    = "failed"    // could not be sent
+)
@@ -6,6 +6,8 @@ import { firstValueFrom } from 'rxjs';
 export class RegistryService {
     private readonly logger = new Logger(RegistryService.name);
 
+    private static readonly JOB_POLL_MS = 1000;
+
     // Mock on-chain registry
     private registry = new Map<string, any>();
 
@@ -41,28 +43,19 @@ export class RegistryService {
                 is_mock: submissionData.isMock
             };
 
-            const response = await firstValueFrom(
-                this.httpService.post(`${oracleUrl}/verify`, oraclePayload)
-            ) as any;
-
-            const oracleResult = response.data;
-            const txHash = oracleResult.attestation?.signature || '0xmocktransactionhash'; // The Go aggregator returns a signature, but PushAttestation returns hash. Wait, verify returns OracleResult structure.
-            // OracleResult: { attestation: { signature, merkle_root, ... } }
-            // The Go aggregator prints the txHash to stdout but doesn't seem to include it in OracleResult explicitly in the `AttestationData`.
-            // Wait, looking at aggregator.go: 
-            // `AttestationData` struct has `Signature`. 
-            // The `txHash` is printed but not returned in the struct.
-            // I should have updated the `OracleResult` or `AttestationData` to include `TxHash`.
-
-            // Let's assume for now we just log it or use a placeholder if the Go oracle doesn't return the TX hash in the JSON.
-            // But relying on "0xmock..." defeats the purpose.
-            // I should update Go types first. But I am already here.
-            // Let's tentatively use a field if it exists, or fall back.
+            // POST /verify queues a job (202); poll it until the oracle has signed
+            // and, when a chain is configured, sent the attestation to the registry
+            const job = await this.runOracleJob(oracleUrl, oraclePayload);
+            const attestation = job.result?.attestation;
 
             const registrationRecord = {
                 assetId: submissionId,
                 ...consensusResult,
-                txHash: (oracleResult as any).tx_hash || txHash, // Need to ensure Go returns it
+                txHash: job.tx_hash || null, // unset when the oracle runs without a chain
+                oracleJobId: job.id,
+                oracleStatus: job.status,
+                merkleRoot: attestation?.merkle_root,
+                oracleSignature: attestation?.signature,
                 registeredAt: new Date().toISOString(),
                 isMock: submissionData.isMock
             };
@@ -72,11 +65,13 @@ export class RegistryService {
 
         } catch (error) {
             this.logger.error(`Failed to register on-chain: ${error.message}`);
-            // Fallback for demo stability if Oracle is down
+            // Keep the demo flow going if the Oracle is down, without claiming a transaction
             const registrationRecord = {
                 assetId: submissionId,
                 ...consensusResult,
-                txHash: '0xmockfallback',
+                txHash: null,
+                oracleStatus: 'unavailable',
+                oracleError: error.message,
                 registeredAt: new Date().toISOString(),
                 isMock: submissionData.isMock
             };
@@ -84,4 +79,30 @@ export class RegistryService {
             return registrationRecord;
         }
     }
+
+    // Submits to the oracle's job queue and waits for the job to finish.
+    // Resolves with the job once it is signed or anchored; rejects if it fails or times out.
+    private async runOracleJob(oracleUrl: string, payload: any): Promise<any> {
+        const { data: queued } = await firstValueFrom(
+            this.httpService.post(`${oracleUrl}/verify`, payload)
+        ) as any;
+
+        const timeoutMs = Number(process.env.ORACLE_JOB_TIMEOUT_MS) || 120000;
+        const deadline = Date.now() + timeoutMs;
+        let job = queued;
+        while (job.status === 'queued' || job.status === 'running') {
+            if (Date.now() > deadline) {
+                throw new Error(`oracle job ${queued.id} still ${job.status} after ${timeoutMs}ms`);
+            }
+            await new Promise((resolve) => setTimeout(resolve, RegistryService.JOB_POLL_MS));
+            ({ data: job } = await firstValueFrom(
+                this.httpService.get(`${oracleUrl}/jobs/${queued.id}`)
+            ) as any);
+        }
+
+        if (job.status === 'failed') {
+            throw new Error(`oracle job ${job.id} failed: ${job.error}`);
+        }
+        return job;
+    }
 }